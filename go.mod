@@ -3,6 +3,7 @@ module github.com/jakebark/tag-nag
 go 1.22
 
 require (
+	github.com/google/go-cmp v0.6.0
 	github.com/hashicorp/hcl/v2 v2.23.0
 	github.com/spf13/pflag v1.0.6
 	github.com/zclconf/go-cty v1.13.0
@@ -13,7 +14,6 @@ require (
 	github.com/agext/levenshtein v1.2.1 // indirect
 	github.com/apparentlymart/go-textseg/v13 v13.0.0 // indirect
 	github.com/apparentlymart/go-textseg/v15 v15.0.0 // indirect
-	github.com/google/go-cmp v0.6.0 // indirect
 	github.com/mitchellh/go-wordwrap v0.0.0-20150314170334-ad45545899c7 // indirect
 	golang.org/x/mod v0.8.0 // indirect
 	golang.org/x/sys v0.5.0 // indirect
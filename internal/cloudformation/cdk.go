@@ -0,0 +1,95 @@
+package cloudformation
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/jakebark/tag-nag/internal/shared"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	cdkManifestFile = "manifest.json"
+	cdkMetadataType = "AWS::CDK::Metadata" // added to every cdk stack, cannot be tagged
+)
+
+// cdkManifest is the manifest of a cdk cloud assembly, eg cdk.out/manifest.json
+type cdkManifest struct {
+	Artifacts map[string]cdkArtifact `json:"artifacts"`
+}
+
+type cdkArtifact struct {
+	Type       string `json:"type"`
+	Properties struct {
+		TemplateFile  string            `json:"templateFile"`  // stacks
+		Tags          map[string]string `json:"tags"`          // stack tags
+		DirectoryName string            `json:"directoryName"` // nested assemblies, eg stages
+	} `json:"properties"`
+}
+
+// readManifest reads the manifest of a cdk cloud assembly, or returns false when the directory is not one
+func readManifest(directoryPath string) (*cdkManifest, bool) {
+	data, err := os.ReadFile(filepath.Join(directoryPath, cdkManifestFile))
+	if err != nil {
+		return nil, false
+	}
+	var manifest cdkManifest
+	if err := json.Unmarshal(data, &manifest); err != nil || manifest.Artifacts == nil {
+		return nil, false
+	}
+	return &manifest, true
+}
+
+// processAssembly checks the synthesized templates of a cdk cloud assembly, its stacks, nested stacks and stages
+// stack tags from the manifest are inherited by every resource in the stack, over any given stack tags
+func processAssembly(templates *templateSet, directoryPath string, manifest *cdkManifest, rules shared.Rules, caseInsensitive bool, taggable map[string]bool, stackTags shared.TagMap) ([]shared.Violation, []shared.Resource, []shared.ParseError) {
+	var allViolations []shared.Violation
+	var allResources []shared.Resource
+	var allParseErrors []shared.ParseError
+
+	names := make([]string, 0, len(manifest.Artifacts))
+	for name := range manifest.Artifacts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		artifact := manifest.Artifacts[name]
+		var violations []shared.Violation
+		var resources []shared.Resource
+		var parseErrors []shared.ParseError
+		switch artifact.Type {
+		case "aws:cloudformation:stack":
+			if artifact.Properties.TemplateFile == "" {
+				continue
+			}
+			tags := shared.MergeTags(stackTags, literalTagMap(artifact.Properties.Tags))
+			violations, resources, parseErrors = processStack(templates, filepath.Join(directoryPath, artifact.Properties.TemplateFile), rules, caseInsensitive, taggable, true, tags, make(map[string]bool))
+		case "cdk:cloud-assembly":
+			if artifact.Properties.DirectoryName == "" {
+				continue
+			}
+			nestedPath := filepath.Join(directoryPath, artifact.Properties.DirectoryName)
+			nested, ok := readManifest(nestedPath)
+			if !ok {
+				continue
+			}
+			violations, resources, parseErrors = processAssembly(templates, nestedPath, nested, rules, caseInsensitive, taggable, stackTags)
+		}
+		allViolations = append(allViolations, violations...)
+		allResources = append(allResources, resources...)
+		allParseErrors = append(allParseErrors, parseErrors...)
+	}
+	return allViolations, allResources, allParseErrors
+}
+
+// constructPath returns the cdk construct path of a resource, from its aws:cdk:path metadata, eg "MyStack/Bucket/Resource"
+func constructPath(metadataNode *yaml.Node) string {
+	pathNode, ok := mapNodes(metadataNode)["aws:cdk:path"]
+	if !ok || pathNode.Kind != yaml.ScalarNode {
+		return ""
+	}
+	return pathNode.Value
+}
@@ -0,0 +1,92 @@
+package cloudformation
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/jakebark/tag-nag/internal/shared"
+)
+
+func TestProcessAssembly(t *testing.T) {
+	bucket := func(path string) string {
+		return `{"Resources": {"Bucket": {"Type": "AWS::S3::Bucket", "Metadata": {"aws:cdk:path": "` + path + `"}}, ` +
+			`"CDKMetadata": {"Type": "AWS::CDK::Metadata", "Properties": {"Analytics": "v2"}}}}`
+	}
+	files := map[string]string{
+		"cdk.out/manifest.json": `{"artifacts": {
+			"App": {"type": "aws:cloudformation:stack", "properties": {"templateFile": "App.template.json"}},
+			"assembly-Prod": {"type": "cdk:cloud-assembly", "properties": {"directoryName": "assembly-Prod"}},
+			"Tree": {"type": "cdk:tree", "properties": {"file": "tree.json"}}
+		}}`,
+		"cdk.out/App.template.json": `{"Resources": {
+			"Bucket": {"Type": "AWS::S3::Bucket", "Metadata": {"aws:cdk:path": "App/Bucket/Resource"}},
+			"Nested": {"Type": "AWS::CloudFormation::Stack", "Properties": {"Tags": [{"Key": "Environment", "Value": "dev"}]},
+				"Metadata": {"aws:cdk:path": "App/Nested.NestedStack/Nested.NestedStackResource", "aws:asset:path": "AppNested.nested.template.json"}}
+		}}`,
+		"cdk.out/AppNested.nested.template.json":                bucket("App/Nested/Bucket/Resource"),
+		"cdk.out/assembly-Prod/manifest.json":                   `{"artifacts": {"ProdApp": {"type": "aws:cloudformation:stack", "properties": {"templateFile": "ProdApp.template.json"}}}}`,
+		"cdk.out/assembly-Prod/ProdApp.template.json":           bucket("Prod/App/Bucket/Resource"),
+		"cdk.out/asset.1234/template.json":                      bucket("not/in/the/manifest"),
+		"cdk.out/tree.json":                                     `{"version": "tree-0.1"}`,
+		"cdk.out/assembly-Prod/asset.5678/Unused.template.json": bucket("not/in/the/manifest"),
+	}
+
+	dir := t.TempDir()
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	rules := shared.Rules{RequiredTags: shared.TagMap{"Owner": {}}}
+	for _, scanned := range []string{dir, filepath.Join(dir, "cdk.out")} {
+		violations, resources, _ := ProcessDirectory(scanned, rules, false, "", nil, nil, nil)
+
+		var actual []string
+		for _, v := range violations {
+			actual = append(actual, v.Construct)
+		}
+		sort.Strings(actual)
+		expected := []string{"App/Bucket/Resource", "App/Nested.NestedStack/Nested.NestedStackResource", "App/Nested/Bucket/Resource", "Prod/App/Bucket/Resource"}
+		if diff := cmp.Diff(expected, actual); diff != "" {
+			t.Errorf("ProcessDirectory(%s) constructs mismatch (-want +got):\n%s", scanned, diff)
+		}
+		if len(resources) != 4 {
+			t.Errorf("ProcessDirectory(%s) resources = %d; want 4", scanned, len(resources))
+		}
+	}
+}
+
+func TestReadManifest(t *testing.T) {
+	testCases := []struct {
+		name     string
+		manifest string
+		expected bool
+	}{
+		{name: "cloud assembly", manifest: `{"version": "36.0.0", "artifacts": {}}`, expected: true},
+		{name: "other manifest", manifest: `{"name": "app", "version": "1.0.0"}`},
+		{name: "invalid json", manifest: `{"artifacts": `},
+		{name: "no manifest"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			dir := t.TempDir()
+			if tc.manifest != "" {
+				if err := os.WriteFile(filepath.Join(dir, cdkManifestFile), []byte(tc.manifest), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if _, ok := readManifest(dir); ok != tc.expected {
+				t.Errorf("readManifest() = %v; want %v", ok, tc.expected)
+			}
+		})
+	}
+}
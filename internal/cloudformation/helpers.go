@@ -1,10 +1,12 @@
 package cloudformation
 
 import (
-	"os"
+	"bytes"
+	"regexp"
+	"strconv"
 	"strings"
 
-	"github.com/jakebark/tag-nag/internal/config"
+	"github.com/jakebark/tag-nag/internal/shared"
 	"gopkg.in/yaml.v3"
 )
 
@@ -40,30 +42,254 @@ func findMapNode(node *yaml.Node, key string) *yaml.Node {
 	return nil
 }
 
-// parseYAML unmarshal yaml and return a pointer to the root of the node
-func parseYAML(filePath string) (*yaml.Node, error) {
-	data, err := os.ReadFile(filePath)
-	if err != nil {
-		return nil, err
+// isTemplate reports whether a parsed file is a cfn template, rather than other yaml or json,
+// by an AWSTemplateFormatVersion or Transform key, or Resources with an AWS:: type
+func isTemplate(root *yaml.Node) bool {
+	if findMapNode(root, "AWSTemplateFormatVersion") != nil || findMapNode(root, "Transform") != nil {
+		return true
+	}
+	resourcesNode := findMapNode(root, "Resources")
+	if resourcesNode == nil || resourcesNode.Kind != yaml.MappingNode {
+		return false
+	}
+	for i := 1; i < len(resourcesNode.Content); i += 2 {
+		typeNode := mapNodes(resourcesNode.Content[i])["Type"]
+		if typeNode != nil && strings.HasPrefix(typeNode.Value, "AWS::") {
+			return true
+		}
 	}
+	return false
+}
 
+// looksLikeTemplate guesses whether a file that cannot be parsed was meant to be a cfn template
+func looksLikeTemplate(data []byte) bool {
+	return bytes.Contains(data, []byte("AWSTemplateFormatVersion")) || bytes.Contains(data, []byte("AWS::"))
+}
+
+// parseYAML unmarshal yaml and return a pointer to the root of the node, with short-form intrinsics expanded
+func parseYAML(data []byte) (*yaml.Node, error) {
 	var root yaml.Node
 	if err := yaml.Unmarshal(data, &root); err != nil {
 		return nil, err
 	}
 
 	if root.Kind == yaml.DocumentNode && len(root.Content) > 0 {
-		root = *root.Content[0]
+		content := *root.Content[0]
+		// keep comments at the top or bottom of the file, eg ignore-all
+		content.HeadComment = strings.TrimSpace(root.HeadComment + "\n" + content.HeadComment)
+		content.FootComment = strings.TrimSpace(content.FootComment + "\n" + root.FootComment)
+		root = content
 	}
+	expandIntrinsics(&root)
 	return &root, nil
 }
 
-func skipResource(node *yaml.Node, lines []string) bool {
-	index := node.Line - 2
-	if index < len(lines) {
-		if strings.Contains(lines[index], config.TagNagIgnore) {
-			return true
+var yamlErrorPattern = regexp.MustCompile(`^yaml: line (\d+): `)
+
+// yamlErrorLine splits a yaml error into its line, 0 when it has none, and the message without the prefix
+func yamlErrorLine(err error) (int, string) {
+	message := err.Error()
+	match := yamlErrorPattern.FindStringSubmatch(message)
+	if match == nil {
+		return 0, strings.TrimPrefix(message, "yaml: ")
+	}
+	line, _ := strconv.Atoi(match[1])
+	return line, message[len(match[0]):]
+}
+
+// yamlComment is a comment line and the node it is attached to
+type yamlComment struct {
+	shared.Comment
+	node *yaml.Node
+	foot bool // after the node, rather than above or beside it
+}
+
+// skipResource returns the ignore comment for a resource, if present
+// comments above the resource, on its key line or on its first property are checked
+func skipResource(keyNode *yaml.Node, valueNode *yaml.Node, comments []yamlComment) (shared.Ignore, bool, error) {
+	var firstProperty *yaml.Node
+	if valueNode.Kind == yaml.MappingNode && len(valueNode.Content) > 0 {
+		firstProperty = valueNode.Content[0]
+	}
+
+	for _, comment := range comments {
+		if comment.foot || (comment.node != keyNode && comment.node != valueNode && comment.node != firstProperty) {
+			continue
+		}
+		ignore, found, err := shared.ParseIgnore(comment.Text)
+		if found && !ignore.All { // ignore-all applies to the whole file
+			ignore.Line = comment.Line
+			return ignore, true, err
 		}
 	}
-	return false
+	return shared.Ignore{}, false, nil
+}
+
+// collectComments returns every comment line in a document, in order
+// yaml nodes do not record comment positions, so lines are found by matching the comment text
+func collectComments(root *yaml.Node, lines []string) []yamlComment {
+	var comments []yamlComment
+	cursor := 0
+
+	add := func(node *yaml.Node, text string, foot bool) {
+		for _, commentLine := range strings.Split(text, "\n") {
+			commentLine = strings.TrimSpace(commentLine)
+			if commentLine == "" {
+				continue
+			}
+			line := node.Line
+			for i := cursor; i < len(lines); i++ {
+				if lineComment(lines[i]) == commentLine {
+					line, cursor = i+1, i+1
+					break
+				}
+			}
+			comments = append(comments, yamlComment{Comment: shared.Comment{Text: commentLine, Line: line}, node: node, foot: foot})
+		}
+	}
+
+	var walk func(node *yaml.Node)
+	walk = func(node *yaml.Node) {
+		add(node, node.HeadComment, false)
+		add(node, node.LineComment, false)
+		for _, child := range node.Content {
+			walk(child)
+		}
+		add(node, node.FootComment, true)
+	}
+	walk(root)
+	return comments
+}
+
+// lineComment returns the comment part of a yaml line, a # at the start or after whitespace
+func lineComment(line string) string {
+	for i, r := range line {
+		if r == '#' && (i == 0 || line[i-1] == ' ' || line[i-1] == '\t') {
+			return strings.TrimSpace(line[i:])
+		}
+	}
+	return ""
+}
+
+// commentTexts drops the nodes from yaml comments
+func commentTexts(comments []yamlComment) []shared.Comment {
+	texts := make([]shared.Comment, len(comments))
+	for i, comment := range comments {
+		texts[i] = comment.Comment
+	}
+	return texts
+}
+
+// lastLine returns the last line a node spans
+func lastLine(node *yaml.Node) int {
+	line := node.Line
+	if node.Kind == yaml.ScalarNode && node.Style&(yaml.LiteralStyle|yaml.FoldedStyle) != 0 {
+		line += strings.Count(strings.TrimSuffix(node.Value, "\n"), "\n") + 1 // block scalars start on the line after the indicator
+	}
+	for _, child := range node.Content {
+		if childLine := lastLine(child); childLine > line {
+			line = childLine
+		}
+	}
+	return line
+}
+
+// tagFix returns where missing tags can be added to a resource's properties, or nil when they can't be edited safely
+// eg json templates, or tags set with an intrinsic function
+func tagFix(propsNode *yaml.Node) *shared.TagFix {
+	if propsNode == nil || propsNode.Kind != yaml.MappingNode || propsNode.Style&yaml.FlowStyle != 0 || len(propsNode.Content) == 0 {
+		return nil
+	}
+
+	tagsNode, ok := mapNodes(propsNode)["Tags"]
+	if !ok {
+		return &shared.TagFix{
+			Line:   lastLine(propsNode) + 1,
+			Indent: strings.Repeat(" ", propsNode.Content[0].Column-1),
+			Style:  shared.FixYAMLAttribute,
+		}
+	}
+
+	if tagsNode.Kind != yaml.SequenceNode || tagsNode.Style&yaml.FlowStyle != 0 || len(tagsNode.Content) == 0 {
+		return nil
+	}
+	firstTag := tagsNode.Content[0]
+	if firstTag.Column < 3 {
+		return nil
+	}
+	return &shared.TagFix{
+		Line:   lastLine(tagsNode) + 1,
+		Indent: strings.Repeat(" ", firstTag.Column-3), // items start "- "
+		Style:  shared.FixYAMLList,
+	}
+}
+
+// tagLocations are the lines of a resource's Tags node, and of each tag's key and value
+type tagLocations struct {
+	tags   int
+	keys   map[string]int
+	values map[string]int
+}
+
+// locateTags finds the lines of the tags in a resource's properties
+func locateTags(propsNode *yaml.Node) tagLocations {
+	locations := tagLocations{keys: make(map[string]int), values: make(map[string]int)}
+	if propsNode == nil || propsNode.Kind != yaml.MappingNode {
+		return locations
+	}
+	var tagsNode *yaml.Node
+	for i := 0; i+1 < len(propsNode.Content); i += 2 {
+		if propsNode.Content[i].Value == "Tags" {
+			locations.tags = propsNode.Content[i].Line // the key, a block sequence starts on the next line
+			tagsNode = propsNode.Content[i+1]
+		}
+	}
+	if tagsNode == nil || tagsNode.Kind != yaml.SequenceNode {
+		return locations
+	}
+	for _, entryNode := range tagsNode.Content {
+		entry := mapNodes(entryNode)
+		keyNode, ok := entry["Key"]
+		if !ok || keyNode.Kind != yaml.ScalarNode {
+			continue
+		}
+		locations.keys[keyNode.Value] = keyNode.Line
+		locations.values[keyNode.Value] = keyNode.Line
+		if valueNode, ok := entry["Value"]; ok {
+			locations.values[keyNode.Value] = valueNode.Line
+		}
+	}
+	return locations
+}
+
+// findingLine returns the line of a finding, a bad value's line, a bad key's line, or the Tags node for duplicate keys
+// missing and inherited tags are left on the resource's line
+func (l tagLocations) findingLine(finding shared.Finding, caseInsensitive bool) int {
+	if finding.InheritedFrom != "" {
+		return 0 // inherited from the stack tags, not set on the resource
+	}
+	switch finding.RuleID {
+	case shared.RuleInvalidTagValue:
+		return l.lookup(l.values, finding.Tag, caseInsensitive)
+	case shared.RuleTagKeyStyle:
+		return l.lookup(l.keys, finding.Tag, caseInsensitive)
+	case shared.RuleDuplicateTagKey:
+		return l.tags
+	}
+	return 0
+}
+
+// lookup returns the line of a tag key, falling back to the Tags node
+func (l tagLocations) lookup(lines map[string]int, key string, caseInsensitive bool) int {
+	if line, ok := lines[key]; ok {
+		return line
+	}
+	if caseInsensitive {
+		for k, line := range lines {
+			if strings.EqualFold(k, key) {
+				return line
+			}
+		}
+	}
+	return l.tags
 }
@@ -1,8 +1,10 @@
 package cloudformation
 
 import (
+	"strings"
 	"testing"
 
+	"github.com/jakebark/tag-nag/internal/shared"
 	"gopkg.in/yaml.v3"
 )
 
@@ -142,3 +144,271 @@ func testFindMapNode(t *testing.T) {
 	}
 }
 
+func TestSkipResource(t *testing.T) {
+	yamlContent := `
+# owned by platform
+
+#tag-nag ignore reason="above"
+Above:
+  Type: AWS::S3::Bucket
+Header:  # tag-nag ignore reason="header"
+  Type: AWS::S3::Bucket
+Inside:
+  #tag-nag ignore reason="inside"
+  Type: AWS::S3::Bucket
+Later:
+  Type: AWS::S3::Bucket
+  #tag-nag ignore
+  Properties:
+    BucketName: "#tag-nag ignore"
+FileLevel:
+  #tag-nag ignore-all
+  Type: AWS::S3::Bucket
+`
+	resources := createYamlNode(t, yamlContent)
+	comments := collectComments(resources, strings.Split(yamlContent, "\n"))
+
+	expected := map[string]string{"Above": "above", "Header": "header", "Inside": "inside"}
+	expectedLines := map[string]int{"Above": 4, "Header": 7, "Inside": 10}
+
+	for i := 0; i+1 < len(resources.Content); i += 2 {
+		keyNode, valueNode := resources.Content[i], resources.Content[i+1]
+		t.Run(keyNode.Value, func(t *testing.T) {
+			ignore, found, err := skipResource(keyNode, valueNode, comments)
+			if err != nil {
+				t.Fatalf("skipResource() error = %v", err)
+			}
+			expectedReason, expectedFound := expected[keyNode.Value]
+			if found != expectedFound {
+				t.Errorf("skipResource() found = %v, want %v", found, expectedFound)
+			}
+			if ignore.Reason != expectedReason {
+				t.Errorf("skipResource() reason = %q, want %q", ignore.Reason, expectedReason)
+			}
+			if ignore.Line != expectedLines[keyNode.Value] {
+				t.Errorf("skipResource() line = %d, want %d", ignore.Line, expectedLines[keyNode.Value])
+			}
+		})
+	}
+}
+
+func TestTagFix(t *testing.T) {
+	testCases := []struct {
+		name     string
+		content  string
+		expected string // template with an Owner tag added, empty when there should be no fix
+	}{
+		{
+			name:     "tags list",
+			content:  "Bucket:\n  Type: AWS::S3::Bucket\n  Properties:\n    Tags:\n      - Key: Project\n        Value: web\n    BucketName: test\n",
+			expected: "Bucket:\n  Type: AWS::S3::Bucket\n  Properties:\n    Tags:\n      - Key: Project\n        Value: web\n      - Key: \"Owner\"\n        Value: \"\"\n    BucketName: test\n",
+		},
+		{
+			name:     "no tags",
+			content:  "Bucket:\n  Type: AWS::S3::Bucket\n  Properties:\n    Description: |\n      line one\n      line two\nOther:\n  Type: AWS::S3::Bucket\n",
+			expected: "Bucket:\n  Type: AWS::S3::Bucket\n  Properties:\n    Description: |\n      line one\n      line two\n    Tags:\n      - Key: \"Owner\"\n        Value: \"\"\nOther:\n  Type: AWS::S3::Bucket\n",
+		},
+		{
+			name:    "flow style properties",
+			content: "Bucket:\n  Type: AWS::S3::Bucket\n  Properties: {\"BucketName\": \"test\"}\n",
+		},
+		{
+			name:    "tags from an intrinsic function",
+			content: "Bucket:\n  Type: AWS::S3::Bucket\n  Properties:\n    Tags:\n      Fn::If: [IsProd, [], []]\n",
+		},
+		{
+			name:    "no properties",
+			content: "Bucket:\n  Type: AWS::S3::Bucket\n",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			resources := createYamlNode(t, tc.content)
+			resource := mapNodes(resources)["Bucket"]
+
+			fix := tagFix(mapNodes(resource)["Properties"])
+			if fix == nil {
+				if tc.expected != "" {
+					t.Fatalf("tagFix() = nil, want a fix")
+				}
+				return
+			}
+			if tc.expected == "" {
+				t.Fatalf("tagFix() = %+v, want nil", fix)
+			}
+
+			lines := strings.SplitAfter(tc.content, "\n")
+			fixed := strings.Join(lines[:fix.Line-1], "") + fix.Text("Owner", "") + strings.Join(lines[fix.Line-1:], "")
+			if fixed != tc.expected {
+				t.Errorf("fixed template = %q, want %q", fixed, tc.expected)
+			}
+			var parsed map[string]any
+			if err := yaml.Unmarshal([]byte(fixed), &parsed); err != nil {
+				t.Errorf("fixed template does not parse: %v", err)
+			}
+		})
+	}
+}
+
+func TestYAMLErrorLine(t *testing.T) {
+	testCases := []struct {
+		name            string
+		yamlStr         string
+		expectedLine    int
+		expectedMessage string
+	}{
+		{
+			name:            "tab indentation",
+			yamlStr:         "Resources:\n\tBucket: {}\n",
+			expectedLine:    2,
+			expectedMessage: "found character that cannot start any token",
+		},
+		{
+			name:            "nested mapping on one line",
+			yamlStr:         "Resources:\n  Bucket: Type: AWS::S3::Bucket\n",
+			expectedLine:    2,
+			expectedMessage: "mapping values are not allowed in this context",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := parseYAML([]byte(tc.yamlStr))
+			if err == nil {
+				t.Fatal("parseYAML() expected an error, but got nil")
+			}
+			line, message := yamlErrorLine(err)
+			if line != tc.expectedLine || !strings.Contains(message, tc.expectedMessage) {
+				t.Errorf("yamlErrorLine(%q) = %d, %q; want %d, %q", err, line, message, tc.expectedLine, tc.expectedMessage)
+			}
+		})
+	}
+}
+
+func TestIsTemplate(t *testing.T) {
+	testCases := []struct {
+		name     string
+		content  string
+		expected bool
+	}{
+		{
+			name:     "format version",
+			content:  "AWSTemplateFormatVersion: '2010-09-09'\nDescription: empty\n",
+			expected: true,
+		},
+		{
+			name:     "transform",
+			content:  "Transform: AWS::Serverless-2016-10-31\nResources: {}\n",
+			expected: true,
+		},
+		{
+			name:     "aws resource type",
+			content:  "Resources:\n  Bucket:\n    Type: AWS::S3::Bucket\n",
+			expected: true,
+		},
+		{
+			name:     "json template",
+			content:  `{"Resources": {"Bucket": {"Type": "AWS::S3::Bucket"}}}`,
+			expected: true,
+		},
+		{
+			name:     "other resources",
+			content:  "resources:\n  limits:\n    cpu: 100m\n",
+			expected: false,
+		},
+		{
+			name:     "resources without aws types",
+			content:  "Resources:\n  Thing:\n    Type: Custom\n",
+			expected: false,
+		},
+		{
+			name:     "package.json",
+			content:  `{"name": "app", "version": "1.0.0"}`,
+			expected: false,
+		},
+		{
+			name:     "github workflow",
+			content:  "on: push\njobs:\n  test:\n    runs-on: ubuntu-latest\n",
+			expected: false,
+		},
+		{
+			name:     "empty",
+			content:  "",
+			expected: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			root, err := parseYAML([]byte(tc.content))
+			if err != nil {
+				t.Fatalf("parseYAML() error = %v", err)
+			}
+			if actual := isTemplate(root); actual != tc.expected {
+				t.Errorf("isTemplate() = %v; want %v", actual, tc.expected)
+			}
+		})
+	}
+}
+
+func TestLocateTags(t *testing.T) {
+	yamlTemplate := `Bucket:
+  Type: AWS::S3::Bucket
+  Properties:
+    Tags:
+      - Key: Owner
+        Value: jake
+      - Key: env
+        Value:
+          !Sub "${Env}-app"
+`
+	jsonTemplate := `{
+  "Bucket": {
+    "Type": "AWS::S3::Bucket",
+    "Properties": {
+      "Tags": [
+        {"Key": "Owner", "Value": "jake"},
+        {
+          "Key": "env",
+          "Value": {"Fn::Sub": "${Env}-app"}
+        }
+      ]
+    }
+  }
+}`
+
+	testCases := []struct {
+		name            string
+		finding         shared.Finding
+		caseInsensitive bool
+		expectedYAML    int
+		expectedJSON    int
+	}{
+		{name: "missing tag", finding: shared.Finding{RuleID: shared.RuleMissingTag, Tag: "Project"}},
+		{name: "invalid value", finding: shared.Finding{RuleID: shared.RuleInvalidTagValue, Tag: "Owner"}, expectedYAML: 6, expectedJSON: 6},
+		{name: "invalid value on the next line", finding: shared.Finding{RuleID: shared.RuleInvalidTagValue, Tag: "env"}, expectedYAML: 9, expectedJSON: 9},
+		{name: "invalid value, case insensitive", finding: shared.Finding{RuleID: shared.RuleInvalidTagValue, Tag: "Env"}, caseInsensitive: true, expectedYAML: 9, expectedJSON: 9},
+		{name: "key style", finding: shared.Finding{RuleID: shared.RuleTagKeyStyle, Tag: "env"}, expectedYAML: 7, expectedJSON: 8},
+		{name: "case duplicate", finding: shared.Finding{RuleID: shared.RuleDuplicateTagKey, Tag: "env/Env"}, expectedYAML: 4, expectedJSON: 5},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			for format, template := range map[string]string{"yaml": yamlTemplate, "json": jsonTemplate} {
+				root, err := parseYAML([]byte(template))
+				if err != nil {
+					t.Fatalf("parseYAML(%s) error = %v", format, err)
+				}
+				props := mapNodes(mapNodes(root)["Bucket"])["Properties"]
+				expected := tc.expectedYAML
+				if format == "json" {
+					expected = tc.expectedJSON
+				}
+				if line := locateTags(props).findingLine(tc.finding, tc.caseInsensitive); line != expected {
+					t.Errorf("%s findingLine() = %d; want %d", format, line, expected)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,115 @@
+package cloudformation
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// intrinsicKeys maps yaml short-form tags to their long-form keys
+var intrinsicKeys = map[string]string{
+	"!Ref":          "Ref",
+	"!Condition":    "Condition",
+	"!Base64":       "Fn::Base64",
+	"!Cidr":         "Fn::Cidr",
+	"!FindInMap":    "Fn::FindInMap",
+	"!GetAtt":       "Fn::GetAtt",
+	"!GetAZs":       "Fn::GetAZs",
+	"!ImportValue":  "Fn::ImportValue",
+	"!Join":         "Fn::Join",
+	"!Select":       "Fn::Select",
+	"!Split":        "Fn::Split",
+	"!Sub":          "Fn::Sub",
+	"!Transform":    "Fn::Transform",
+	"!And":          "Fn::And",
+	"!Equals":       "Fn::Equals",
+	"!If":           "Fn::If",
+	"!Not":          "Fn::Not",
+	"!Or":           "Fn::Or",
+	"!Length":       "Fn::Length",
+	"!ToJsonString": "Fn::ToJsonString",
+}
+
+// expandIntrinsics rewrites short-form intrinsics, eg !Sub, into their long-form mappings, eg Fn::Sub,
+// so yaml and json templates decode the same way
+func expandIntrinsics(node *yaml.Node) {
+	for _, child := range node.Content {
+		expandIntrinsics(child)
+	}
+
+	key, ok := intrinsicKeys[node.Tag]
+	if !ok {
+		return
+	}
+
+	value := *node
+	value.HeadComment, value.LineComment, value.FootComment = "", "", ""
+	switch value.Kind {
+	case yaml.ScalarNode:
+		value.Tag = "!!str"
+	case yaml.SequenceNode:
+		value.Tag = "!!seq"
+	case yaml.MappingNode:
+		value.Tag = "!!map"
+	}
+
+	// !GetAtt Resource.Attribute is the short form of [Resource, Attribute]
+	if key == "Fn::GetAtt" && value.Kind == yaml.ScalarNode {
+		if resource, attribute, found := strings.Cut(value.Value, "."); found {
+			value = yaml.Node{
+				Kind:   yaml.SequenceNode,
+				Tag:    "!!seq",
+				Style:  yaml.FlowStyle,
+				Line:   node.Line,
+				Column: node.Column,
+				Content: []*yaml.Node{
+					{Kind: yaml.ScalarNode, Tag: "!!str", Value: resource, Line: node.Line, Column: node.Column},
+					{Kind: yaml.ScalarNode, Tag: "!!str", Value: attribute, Line: node.Line, Column: node.Column},
+				},
+			}
+		}
+	}
+
+	*node = yaml.Node{
+		Kind:        yaml.MappingNode,
+		Tag:         "!!map",
+		Style:       yaml.FlowStyle, // flow style, so tagFix does not offer to add tags inside an intrinsic
+		Line:        node.Line,
+		Column:      node.Column,
+		HeadComment: node.HeadComment,
+		LineComment: node.LineComment,
+		FootComment: node.FootComment,
+		Content: []*yaml.Node{
+			{Kind: yaml.ScalarNode, Tag: "!!str", Value: key, Line: node.Line, Column: node.Column},
+			&value,
+		},
+	}
+}
+
+// intrinsicValue renders a long-form intrinsic in its short form, eg !Sub ${Env}-app, or false when the value is not an intrinsic
+func intrinsicValue(value any) (string, bool) {
+	valueMap, ok := value.(map[string]any)
+	if !ok || len(valueMap) != 1 {
+		return "", false
+	}
+	for key, argument := range valueMap {
+		name, found := strings.CutPrefix(key, "Fn::")
+		if !found && key != "Ref" && key != "Condition" {
+			return "", false
+		}
+		if !found {
+			name = key
+		}
+		if s, ok := argument.(string); ok {
+			return fmt.Sprintf("!%s %s", name, s), true
+		}
+		encoded, err := json.Marshal(argument)
+		if err != nil {
+			return "", false
+		}
+		return fmt.Sprintf("!%s %s", name, encoded), true
+	}
+	return "", false
+}
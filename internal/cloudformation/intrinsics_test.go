@@ -0,0 +1,103 @@
+package cloudformation
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestExpandIntrinsics(t *testing.T) {
+	testCases := []struct {
+		name     string
+		yaml     string
+		json     string
+		expected any
+	}{
+		{
+			name:     "ref",
+			yaml:     `Value: !Ref Env`,
+			json:     `{"Value": {"Ref": "Env"}}`,
+			expected: map[string]any{"Ref": "Env"},
+		},
+		{
+			name:     "sub",
+			yaml:     `Value: !Sub "${Env}-app"`,
+			json:     `{"Value": {"Fn::Sub": "${Env}-app"}}`,
+			expected: map[string]any{"Fn::Sub": "${Env}-app"},
+		},
+		{
+			name:     "getatt",
+			yaml:     `Value: !GetAtt Bucket.Arn`,
+			json:     `{"Value": {"Fn::GetAtt": ["Bucket", "Arn"]}}`,
+			expected: map[string]any{"Fn::GetAtt": []any{"Bucket", "Arn"}},
+		},
+		{
+			name: "nested",
+			yaml: `Value: !If [IsProd, !Sub "${Env}-app", !Ref AWS::NoValue]`,
+			json: `{"Value": {"Fn::If": ["IsProd", {"Fn::Sub": "${Env}-app"}, {"Ref": "AWS::NoValue"}]}}`,
+			expected: map[string]any{"Fn::If": []any{
+				"IsProd",
+				map[string]any{"Fn::Sub": "${Env}-app"},
+				map[string]any{"Ref": "AWS::NoValue"},
+			}},
+		},
+		{
+			name: "block sequence",
+			yaml: "Value: !Join\n  - '-'\n  - - !Ref Env\n    - app",
+			json: `{"Value": {"Fn::Join": ["-", [{"Ref": "Env"}, "app"]]}}`,
+			expected: map[string]any{"Fn::Join": []any{
+				"-",
+				[]any{map[string]any{"Ref": "Env"}, "app"},
+			}},
+		},
+		{
+			name:     "quoted number",
+			yaml:     `Value: !Sub 123`,
+			json:     `{"Value": {"Fn::Sub": "123"}}`,
+			expected: map[string]any{"Fn::Sub": "123"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			for format, data := range map[string]string{"yaml": tc.yaml, "json": tc.json} {
+				root, err := parseYAML([]byte(data))
+				if err != nil {
+					t.Fatalf("parseYAML(%s) error = %v", format, err)
+				}
+				var decoded map[string]any
+				if err := root.Decode(&decoded); err != nil {
+					t.Fatalf("Decode(%s) error = %v", format, err)
+				}
+				if diff := cmp.Diff(tc.expected, decoded["Value"]); diff != "" {
+					t.Errorf("%s mismatch (-expected +got):\n%s", format, diff)
+				}
+			}
+		})
+	}
+}
+
+func TestIntrinsicValue(t *testing.T) {
+	testCases := []struct {
+		name     string
+		value    any
+		expected string
+		ok       bool
+	}{
+		{name: "literal", value: "dev"},
+		{name: "ref", value: map[string]any{"Ref": "Env"}, expected: "!Ref Env", ok: true},
+		{name: "sub", value: map[string]any{"Fn::Sub": "${Env}-app"}, expected: "!Sub ${Env}-app", ok: true},
+		{name: "list argument", value: map[string]any{"Fn::GetAtt": []any{"Bucket", "Arn"}}, expected: `!GetAtt ["Bucket","Arn"]`, ok: true},
+		{name: "not an intrinsic", value: map[string]any{"Key": "Env"}},
+		{name: "more than one key", value: map[string]any{"Ref": "Env", "Fn::Sub": "app"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			actual, ok := intrinsicValue(tc.value)
+			if actual != tc.expected || ok != tc.ok {
+				t.Errorf("intrinsicValue(%v) = %q, %v; want %q, %v", tc.value, actual, ok, tc.expected, tc.ok)
+			}
+		})
+	}
+}
@@ -1,7 +1,6 @@
 package cloudformation
 
 import (
-	"fmt"
 	"log"
 	"os"
 	"path/filepath"
@@ -12,18 +11,25 @@ import (
 	"github.com/jakebark/tag-nag/internal/shared"
 )
 
-// ProcessDirectory walks all cfn files in a directory, then returns violations
-func ProcessDirectory(directoryPath string, requiredTags map[string][]string, caseInsensitive bool, specFilePath string, skip []string) []shared.Violation {
+// ProcessDirectory walks all cfn templates in a directory, then returns violations, every resource checked, and templates that could not be parsed.
+// With include globs, only matching files are checked, otherwise yaml and json files are checked when they look like templates, and cdk cloud assemblies through their manifest.
+// Nested stacks are checked through their parent, and every resource inherits the stack tags
+func ProcessDirectory(directoryPath string, rules shared.Rules, caseInsensitive bool, specFilePath string, skip []string, include []string, stackTags shared.TagMap) ([]shared.Violation, []shared.Resource, []shared.ParseError) {
 	hasFiles, err := scan(directoryPath)
 	if err != nil {
-		return nil
+		return nil, nil, nil
 	}
 	if !hasFiles {
-		return nil
+		return nil, nil, nil
 	}
 
 	// log.Println("\nCloudFormation files found")
 	var allViolations []shared.Violation
+	var allResources []shared.Resource
+	var allParseErrors []shared.ParseError
+	var templatePaths []string
+	includedPaths := make(map[string]bool)
+	templates := newTemplateSet()
 
 	var taggable map[string]bool
 	if specFilePath != "" {
@@ -56,48 +62,89 @@ func ProcessDirectory(directoryPath string, requiredTags map[string][]string, ca
 			if slices.Contains(config.SkippedDirs, dirName) {
 				return filepath.SkipDir
 			}
+			// a cdk cloud assembly, eg cdk.out, is checked through its manifest
+			if manifest, ok := readManifest(path); ok {
+				violations, resources, parseErrors := processAssembly(templates, path, manifest, rules, caseInsensitive, taggable, stackTags)
+				allViolations = append(allViolations, violations...)
+				allResources = append(allResources, resources...)
+				allParseErrors = append(allParseErrors, parseErrors...)
+				return filepath.SkipDir
+			}
 		}
 
 		if !info.IsDir() && (filepath.Ext(path) == ".yaml" || filepath.Ext(path) == ".yml" || filepath.Ext(path) == ".json") {
-			violations, processErr := processFile(path, requiredTags, caseInsensitive, taggable)
-			if processErr != nil {
-				log.Printf("Error processing file %s: %v\n", path, processErr)
-				return nil // Example: Continue walking
+			// a file given directly is always checked
+			included := path == directoryPath
+			if len(include) > 0 && !included {
+				if !matchesInclude(directoryPath, path, include) {
+					return nil
+				}
+				included = true
 			}
-			allViolations = append(allViolations, violations...)
+			templatePaths = append(templatePaths, path)
+			includedPaths[path] = included
 		}
 		return nil
 	})
 	if walkErr != nil {
 		log.Printf("Error scanning directory %s: %v\n", directoryPath, walkErr)
 	}
-	return allViolations
+
+	for _, path := range rootTemplates(templates, templatePaths) {
+		violations, resources, parseErrors := processStack(templates, path, rules, caseInsensitive, taggable, includedPaths[path], stackTags, make(map[string]bool))
+		allViolations = append(allViolations, violations...)
+		allResources = append(allResources, resources...)
+		allParseErrors = append(allParseErrors, parseErrors...)
+	}
+	return allViolations, allResources, allParseErrors
 }
 
-// processFile parses files and maps the cfn nodes
-func processFile(filePath string, requiredTags shared.TagMap, caseInsensitive bool, taggable map[string]bool) ([]shared.Violation, error) {
-	data, err := os.ReadFile(filePath)
-	if err != nil {
-		log.Printf("Error reading %s: %v\n", filePath, err)
-		return nil, fmt.Errorf("reading file %s: %w", filePath, err)
+// processFile parses files and maps the cfn nodes, skipping files that are not templates unless included.
+// a template that cannot be read or parsed is returned as a parse error
+func processFile(file *templateFile, rules shared.Rules, caseInsensitive bool, taggable map[string]bool, included bool, stackTags shared.TagMap) ([]shared.Violation, []shared.Resource, []shared.ParseError) {
+	filePath := file.path
+	if file.readErr != nil {
+		return nil, nil, []shared.ParseError{shared.NewParseError(filePath, 0, file.readErr.Error(), rules.Strict)}
 	}
-	content := string(data)
-	lines := strings.Split(content, "\n")
 
-	skipAll := strings.Contains(content, config.TagNagIgnoreAll)
+	root := file.root
+	if file.parseErr != nil {
+		if !included && !looksLikeTemplate(file.data) {
+			return nil, nil, nil
+		}
+		line, message := yamlErrorLine(file.parseErr)
+		return nil, nil, []shared.ParseError{shared.NewParseError(filePath, line, message, rules.Strict)}
+	}
+	if !included && !isTemplate(root) {
+		return nil, nil, nil
+	}
 
-	root, err := parseYAML(filePath)
+	comments := collectComments(root, strings.Split(string(file.data), "\n"))
+	fileIgnore, err := shared.FindIgnoreAll(commentTexts(comments))
 	if err != nil {
-		return nil, fmt.Errorf("parsing file %s: %w", filePath, err)
+		log.Printf("Invalid ignore-all comment in %s: %v\n", filePath, err)
 	}
 
 	// search root node for resources node
-	resourcesMapping := mapNodes(findMapNode(root, "Resources"))
-	if resourcesMapping == nil {
-		log.Printf("No 'Resources' section found in %s\n", filePath)
-		return []shared.Violation{}, nil
+	resourcesNode := findMapNode(root, "Resources")
+	if resourcesNode == nil {
+		return []shared.Violation{}, nil, nil
 	}
 
-	violations := checkResourcesForTags(resourcesMapping, requiredTags, caseInsensitive, lines, skipAll, taggable, filePath)
-	return violations, nil
+	violations, resources := checkResourcesForTags(resourcesNode, rules, caseInsensitive, comments, fileIgnore, taggable, stackTags, filePath)
+	return violations, resources, nil
+}
+
+// matchesInclude reports whether a file, relative to the scanned directory, matches an include glob
+func matchesInclude(directoryPath string, path string, include []string) bool {
+	rel, err := filepath.Rel(directoryPath, path)
+	if err != nil {
+		return false
+	}
+	for _, pattern := range include {
+		if shared.MatchGlob(pattern, filepath.ToSlash(rel)) {
+			return true
+		}
+	}
+	return false
 }
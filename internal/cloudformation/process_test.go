@@ -0,0 +1,77 @@
+package cloudformation
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/jakebark/tag-nag/internal/shared"
+)
+
+func TestProcessDirectory(t *testing.T) {
+	template := "Resources:\n  Bucket:\n    Type: AWS::S3::Bucket\n"
+	files := map[string]string{
+		"templates/network/vpc.yaml":  template,
+		"templates/app.yml":           template,
+		"stacks/db.json":              `{"Resources": {"Bucket": {"Type": "AWS::S3::Bucket"}}}`,
+		"package.json":                `{"name": "app"}`,
+		".github/workflows/ci.yml":    "on: push\n",
+		"charts/app/templates/x.yaml": "metadata:\n  name: {{ .Values.name }}\n  - broken\n",
+		"broken.yaml":                 "Resources:\n  Bucket:\n    Type: AWS::S3::Bucket\n   Properties: {}\n",
+	}
+
+	dir := t.TempDir()
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	testCases := []struct {
+		name     string
+		include  []string
+		expected []string // files with violations
+	}{
+		{
+			name:     "detects templates",
+			expected: []string{"broken.yaml", "stacks/db.json", "templates/app.yml", "templates/network/vpc.yaml"},
+		},
+		{
+			name:     "include globs",
+			include:  []string{"templates/**/*.yaml"},
+			expected: []string{"templates/network/vpc.yaml"},
+		},
+		{
+			name:     "included files are checked even when not detected",
+			include:  []string{"**/*.json", "charts/**"},
+			expected: []string{"charts/app/templates/x.yaml", "stacks/db.json"},
+		},
+	}
+
+	rules := shared.Rules{RequiredTags: shared.TagMap{"Owner": {}}}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			violations, _, parseErrors := ProcessDirectory(dir, rules, false, "", nil, tc.include, nil)
+
+			var actual []string
+			for _, v := range violations {
+				rel, _ := filepath.Rel(dir, v.FilePath)
+				actual = append(actual, filepath.ToSlash(rel))
+			}
+			for _, e := range parseErrors {
+				rel, _ := filepath.Rel(dir, e.FilePath)
+				actual = append(actual, filepath.ToSlash(rel))
+			}
+			sort.Strings(actual)
+			if diff := cmp.Diff(tc.expected, actual); diff != "" {
+				t.Errorf("ProcessDirectory() files mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
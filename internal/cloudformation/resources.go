@@ -4,27 +4,59 @@ import (
 	"fmt"
 	"log"
 	"strings"
+	"time"
 
 	"github.com/jakebark/tag-nag/internal/shared"
 	"gopkg.in/yaml.v3"
 )
 
 // getResourceViolations inspects resource blocks and returns violations
-func checkResourcesForTags(resourcesMapping map[string]*yaml.Node, requiredTags shared.TagMap, caseInsensitive bool, fileLines []string, skipAll bool, taggable map[string]bool, filePath string) []shared.Violation {
+// stack tags are layered under each resource's tags, as CloudFormation propagates them
+func checkResourcesForTags(resourcesNode *yaml.Node, rules shared.Rules, caseInsensitive bool, comments []yamlComment, fileIgnore *shared.Ignore, taggable map[string]bool, stackTags shared.TagMap, filePath string) ([]shared.Violation, []shared.Resource) {
 	var violations []shared.Violation
+	var resources []shared.Resource
+	var attachedIgnores []shared.Ignore
+	var uncheckedIgnores []shared.Ignore
+	fileIgnoreUsed := false
 
-	for resourceName, resourceNode := range resourcesMapping { // resourceNode == yaml node for resource
+	for i := 0; resourcesNode.Kind == yaml.MappingNode && i+1 < len(resourcesNode.Content); i += 2 {
+		keyNode, resourceNode := resourcesNode.Content[i], resourcesNode.Content[i+1] // resourceNode == yaml node for resource
+		resourceName := keyNode.Value
 		resourceMapping := mapNodes(resourceNode)
 
+		var resourceIgnore *shared.Ignore
+		ignore, found, err := skipResource(keyNode, resourceNode, comments)
+		if err != nil {
+			log.Printf("Invalid ignore comment for %s in %s: %v\n", resourceName, filePath, err)
+		}
+		if found {
+			attachedIgnores = append(attachedIgnores, ignore)
+			if err == nil {
+				resourceIgnore = &ignore
+			}
+		}
+
 		typeNode, ok := resourceMapping["Type"]
 		if !ok || !strings.HasPrefix(typeNode.Value, "AWS::") {
+			if found {
+				uncheckedIgnores = append(uncheckedIgnores, ignore) // the resource is not checked, so it can suppress nothing
+			}
 			continue
 		}
 		resourceType := typeNode.Value
+		if resourceType == cdkMetadataType {
+			if found {
+				uncheckedIgnores = append(uncheckedIgnores, ignore) // the resource is not checked, so it can suppress nothing
+			}
+			continue
+		}
 
 		if taggable != nil {
-			isTaggable, found := taggable[resourceType]
-			if found && !isTaggable {
+			isTaggable, known := taggable[resourceType]
+			if known && !isTaggable {
+				if found {
+					uncheckedIgnores = append(uncheckedIgnores, ignore) // the resource is not checked, so it can suppress nothing
+				}
 				continue
 			}
 		}
@@ -34,29 +66,54 @@ func checkResourcesForTags(resourcesMapping map[string]*yaml.Node, requiredTags
 			_ = propsNode.Decode(&properties)
 		}
 
-		tags, err := extractTagMap(properties, caseInsensitive)
+		tags, err := extractTagMap(properties, false) // original case, for key checks
 		if err != nil {
 			log.Printf("Error extracting tags from resource %s: %v\n", resourceName, err)
 			continue
 		}
 
-		missing := shared.FilterMissingTags(requiredTags, tags, caseInsensitive)
-		if len(missing) > 0 {
-			violation := shared.Violation{
-				ResourceName: resourceName,
-				ResourceType: resourceType,
-				Line:         resourceNode.Line,
-				MissingTags:  missing,
-				FilePath:     filePath,
-			}
-			// if file-level or resource-level ignore is found
-			if skipAll || skipResource(resourceNode, fileLines) {
-				violation.Skip = true
+		findings := shared.CheckTags(rules, stackTags, tags, caseInsensitive)
+		locations := locateTags(resourceMapping["Properties"])
+		for i := range findings {
+			if findings[i].FromDefaultTags {
+				findings[i].FromDefaultTags = false // cfn has no default_tags, the tags came from the stack
+				findings[i].InheritedFrom = "stack tags"
 			}
+			findings[i].Line = locations.findingLine(findings[i], caseInsensitive)
+		}
+		violation := shared.Violation{
+			ResourceName: resourceName,
+			ResourceType: resourceType,
+			Construct:    constructPath(resourceMapping["Metadata"]),
+			Line:         keyNode.Line,
+			EndLine:      lastLine(resourceNode),
+			Findings:     findings,
+			FilePath:     filePath,
+			Fix:          tagFix(resourceMapping["Properties"]),
+		}
+		// if resource-level or file-level ignore is found
+		if shared.ApplyIgnores(&violation, resourceIgnore, fileIgnore, rules, time.Now()) {
+			fileIgnoreUsed = true
+		}
+		violation.MissingTags = shared.MissingTags(violation.Findings) // after ignores, so suppressed findings are left out
+		violation.KeyIssues = shared.KeyIssues(violation.Findings)
+		resources = append(resources, shared.Resource{
+			ResourceType: resourceType,
+			ResourceName: resourceName,
+			Line:         keyNode.Line, // the logical id
+			FilePath:     filePath,
+			Tags:         shared.MergeTags(stackTags, tags),
+			Compliant:    violation.Compliant(rules.FailOn), // after ignores, so ignored and below --fail-on findings are left out
+		})
+		if len(violation.Findings) > 0 {
 			violations = append(violations, violation)
 		}
 	}
-	return violations
+
+	if rules.ReportUnusedIgnores {
+		violations = append(violations, shared.UnusedIgnoreViolations(commentTexts(comments), attachedIgnores, uncheckedIgnores, fileIgnore, fileIgnoreUsed, filePath)...)
+	}
+	return violations, resources
 }
 
 // extractTagMap extracts a yaml/json map to a go map
@@ -84,12 +141,8 @@ func extractTagMap(properties map[string]any, caseInsensitive bool) (shared.TagM
 		var tagValue string
 		if valStr, ok := tagEntry["Value"].(string); ok {
 			tagValue = valStr
-		} else if refMap, ok := tagEntry["Value"].(map[string]any); ok {
-			if ref, exists := refMap["Ref"]; exists {
-				if refStr, ok := ref.(string); ok {
-					tagValue = fmt.Sprintf("!Ref %s", refStr)
-				}
-			}
+		} else if intrinsic, ok := intrinsicValue(tagEntry["Value"]); ok {
+			tagValue = intrinsic
 		}
 		key = shared.NormalizeCase(key, caseInsensitive)
 		tagsMap[key] = []string{tagValue}
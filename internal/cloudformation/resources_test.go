@@ -48,30 +48,32 @@ func TestExtractTagMap(t *testing.T) {
 				"Env":   []string{"Dev"},
 			},
 		},
-		// {
-		// 	name: "referenced tags",
-		// 	properties: map[string]any{
-		// 		"Tags": []any{
-		// 			map[string]any{"Key": "StackName", "Value": map[string]any{"Ref": "AWS::StackName"}},
-		// 		},
-		// 	},
-		// 	expected: shared.TagMap{
-		// 		"StackName": []string{"!Ref StackName"},
-		// 	},
-		// },
-		// {
-		// 	name: "mixed tags, literal and referenced",
-		// 	properties: map[string]any{
-		// 		"Tags": []any{
-		// 			map[string]any{"Key": "Owner", "Value": "Jake"},
-		// 			map[string]any{"Key": "StackName", "Value": map[string]any{"Ref": "AWS::StackName"}},
-		// 		},
-		// 	},
-		// 	expected: shared.TagMap{
-		// 		"Owner":     []string{"Jake"},
-		// 		"StackName": []string{"!Ref StackName"},
-		// 	},
-		// },
+		{
+			name: "referenced tags",
+			properties: map[string]any{
+				"Tags": []any{
+					map[string]any{"Key": "StackName", "Value": map[string]any{"Ref": "AWS::StackName"}},
+				},
+			},
+			expected: shared.TagMap{
+				"StackName": []string{"!Ref AWS::StackName"},
+			},
+		},
+		{
+			name: "mixed tags, literal and intrinsic",
+			properties: map[string]any{
+				"Tags": []any{
+					map[string]any{"Key": "Owner", "Value": "Jake"},
+					map[string]any{"Key": "Name", "Value": map[string]any{"Fn::Sub": "${Env}-app"}},
+					map[string]any{"Key": "Env", "Value": map[string]any{"Fn::If": []any{"IsProd", "prod", "dev"}}},
+				},
+			},
+			expected: shared.TagMap{
+				"Owner": []string{"Jake"},
+				"Name":  []string{"!Sub ${Env}-app"},
+				"Env":   []string{`!If ["IsProd","prod","dev"]`},
+			},
+		},
 		{
 			name: "literal tags, case insensitive",
 			properties: map[string]any{
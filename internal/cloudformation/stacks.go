@@ -0,0 +1,199 @@
+package cloudformation
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/jakebark/tag-nag/internal/shared"
+	"gopkg.in/yaml.v3"
+)
+
+// nestedStack is a child template of an AWS::CloudFormation::Stack resource
+type nestedStack struct {
+	templatePath string
+	tags         shared.TagMap // the stack resource's tags, propagated to every resource in the child
+}
+
+// templateFile is a template, read and parsed once
+type templateFile struct {
+	path     string
+	data     []byte
+	root     *yaml.Node // nil when the file could not be read or parsed
+	readErr  error
+	parseErr error
+}
+
+// templateSet holds each template parsed, so a file is only read once however many times it is looked up,
+// and each template checked with the tags it inherited
+type templateSet struct {
+	files   map[string]*templateFile
+	checked map[string]bool
+}
+
+func newTemplateSet() *templateSet {
+	return &templateSet{files: make(map[string]*templateFile), checked: make(map[string]bool)}
+}
+
+// load reads and parses a template, or returns it when it has already been parsed
+func (s *templateSet) load(templatePath string) *templateFile {
+	key := templateKey(templatePath)
+	if file, ok := s.files[key]; ok {
+		return file
+	}
+	file := &templateFile{path: templatePath}
+	file.data, file.readErr = os.ReadFile(templatePath)
+	if file.readErr == nil {
+		file.root, file.parseErr = parseYAML(file.data)
+	}
+	s.files[key] = file
+	return file
+}
+
+// processStack checks a template, then the local templates of its nested stacks, with the parent's stack tags inherited.
+// visited holds the templates above this one, so a template that nests itself is only checked once.
+// A template nested by several parents is checked once for each set of tags it inherits
+func processStack(templates *templateSet, templatePath string, rules shared.Rules, caseInsensitive bool, taggable map[string]bool, included bool, stackTags shared.TagMap, visited map[string]bool) ([]shared.Violation, []shared.Resource, []shared.ParseError) {
+	key := templateKey(templatePath)
+	checkedKey := key + "\x00" + tagsKey(stackTags)
+	if visited[key] || templates.checked[checkedKey] {
+		return nil, nil, nil
+	}
+	templates.checked[checkedKey] = true
+	visited[key] = true
+	defer delete(visited, key)
+
+	file := templates.load(templatePath)
+	violations, resources, parseErrors := processFile(file, rules, caseInsensitive, taggable, included, stackTags)
+	for _, nested := range nestedStacks(file) {
+		nestedViolations, nestedResources, nestedParseErrors := processStack(templates, nested.templatePath, rules, caseInsensitive, taggable, true, shared.MergeTags(stackTags, nested.tags), visited)
+		violations = append(violations, nestedViolations...)
+		resources = append(resources, nestedResources...)
+		parseErrors = append(parseErrors, nestedParseErrors...)
+	}
+	return violations, resources, parseErrors
+}
+
+// nestedStacks returns the local child templates of a template's AWS::CloudFormation::Stack resources,
+// from a TemplateURL that is a local path, or the aws:asset:path metadata cdk adds
+func nestedStacks(file *templateFile) []nestedStack {
+	if file.root == nil {
+		return nil
+	}
+	resourcesNode := findMapNode(file.root, "Resources")
+	if resourcesNode == nil || resourcesNode.Kind != yaml.MappingNode {
+		return nil
+	}
+
+	var stacks []nestedStack
+	for i := 1; i < len(resourcesNode.Content); i += 2 {
+		resource := mapNodes(resourcesNode.Content[i])
+		typeNode, ok := resource["Type"]
+		if !ok || typeNode.Value != "AWS::CloudFormation::Stack" {
+			continue
+		}
+
+		childPath := localTemplatePath(resource)
+		if childPath == "" {
+			continue
+		}
+
+		properties := make(map[string]any)
+		if propsNode, ok := resource["Properties"]; ok {
+			_ = propsNode.Decode(&properties)
+		}
+		tags, _ := extractTagMap(properties, false) // tags that can't be read are not inherited
+		stacks = append(stacks, nestedStack{
+			templatePath: filepath.Join(filepath.Dir(file.path), childPath),
+			tags:         tags,
+		})
+	}
+	return stacks
+}
+
+// localTemplatePath returns the path of a nested stack's template, relative to its parent, or "" when it is not local, eg an s3 url
+func localTemplatePath(resource map[string]*yaml.Node) string {
+	if assetNode, ok := mapNodes(resource["Metadata"])["aws:asset:path"]; ok && assetNode.Kind == yaml.ScalarNode && assetNode.Value != "" {
+		return assetNode.Value
+	}
+	urlNode, ok := mapNodes(resource["Properties"])["TemplateURL"]
+	if !ok || urlNode.Kind != yaml.ScalarNode || urlNode.Value == "" || strings.Contains(urlNode.Value, "://") {
+		return ""
+	}
+	return filepath.FromSlash(urlNode.Value)
+}
+
+// rootTemplates returns the templates to check from, those that are not nested stacks of another template.
+// Nested templates are checked through their parent, but templates only nested by each other, eg a.yaml and b.yaml
+// nesting each other, have no parent to be reached from, so the first of them is a root too
+func rootTemplates(templates *templateSet, templatePaths []string) []string {
+	children := make(map[string][]string)
+	nested := make(map[string]bool)
+	for _, templatePath := range templatePaths {
+		parentKey := templateKey(templatePath)
+		for _, stack := range nestedStacks(templates.load(templatePath)) {
+			key := templateKey(stack.templatePath)
+			children[parentKey] = append(children[parentKey], key)
+			if key != parentKey { // a template that nests itself is still checked
+				nested[key] = true
+			}
+		}
+	}
+
+	reached := make(map[string]bool)
+	var reach func(key string)
+	reach = func(key string) {
+		if reached[key] {
+			return
+		}
+		reached[key] = true
+		for _, child := range children[key] {
+			reach(child)
+		}
+	}
+
+	var roots []string
+	for _, templatePath := range templatePaths {
+		if key := templateKey(templatePath); !nested[key] {
+			roots = append(roots, templatePath)
+			reach(key)
+		}
+	}
+	for _, templatePath := range templatePaths {
+		if key := templateKey(templatePath); !reached[key] {
+			roots = append(roots, templatePath)
+			reach(key)
+		}
+	}
+	return roots
+}
+
+// templateKey identifies a template, however its path was written
+func templateKey(templatePath string) string {
+	if abs, err := filepath.Abs(templatePath); err == nil {
+		return abs
+	}
+	return filepath.Clean(templatePath)
+}
+
+// literalTagMap converts literal tags, eg stack tags, into a tag map
+func literalTagMap(tags map[string]string) shared.TagMap {
+	tagMap := make(shared.TagMap)
+	for key, value := range tags {
+		tagMap[key] = []string{value}
+	}
+	return tagMap
+}
+
+// tagsKey renders tags in a stable order, eg "Env=dev;Owner=platform;"
+func tagsKey(tags shared.TagMap) string {
+	var b strings.Builder
+	for _, key := range shared.TagKeys(tags) {
+		values := append([]string(nil), tags[key]...)
+		sort.Strings(values)
+		fmt.Fprintf(&b, "%s=%s;", key, strings.Join(values, ","))
+	}
+	return b.String()
+}
@@ -0,0 +1,178 @@
+package cloudformation
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/jakebark/tag-nag/internal/shared"
+)
+
+func TestNestedStacks(t *testing.T) {
+	files := map[string]string{
+		"parent.yaml": `AWSTemplateFormatVersion: '2010-09-09'
+Resources:
+  Network:
+    Type: AWS::CloudFormation::Stack
+    Properties:
+      TemplateURL: stacks/network.yaml
+      Tags:
+        - Key: Owner
+          Value: platform
+  Remote:
+    Type: AWS::CloudFormation::Stack
+    Properties:
+      TemplateURL: https://s3.amazonaws.com/bucket/remote.yaml
+      Tags:
+        - Key: Owner
+          Value: platform
+  Bucket:
+    Type: AWS::S3::Bucket
+`,
+		"stacks/network.yaml": `Resources:
+  Vpc:
+    Type: AWS::EC2::VPC
+  Subnets:
+    Type: AWS::CloudFormation::Stack
+    Properties:
+      TemplateURL: ./subnets.json
+      Tags:
+        - Key: Environment
+          Value: prod
+`,
+		"stacks/subnets.json": `{"Resources": {"Subnet": {"Type": "AWS::EC2::Subnet", "Properties": {"Tags": [{"Key": "Owner", "Value": "network"}]}}}}`,
+		"stacks/remote.yaml":  "Resources:\n  Queue:\n    Type: AWS::SQS::Queue\n",
+		"loop.yaml":           "Resources:\n  Self:\n    Type: AWS::CloudFormation::Stack\n    Properties:\n      TemplateURL: loop.yaml\n",
+	}
+
+	dir := t.TempDir()
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	rules := shared.Rules{RequiredTags: shared.TagMap{"Owner": {}, "Environment": {}}}
+	testCases := []struct {
+		name      string
+		stackTags shared.TagMap
+		expected  map[string]shared.TagMap // effective tags of each resource
+	}{
+		{
+			name: "parent tags are inherited",
+			expected: map[string]shared.TagMap{
+				"Network": {"Owner": {"platform"}},
+				"Remote":  {"Owner": {"platform"}},
+				"Bucket":  {},
+				"Vpc":     {"Owner": {"platform"}},
+				"Subnets": {"Owner": {"platform"}, "Environment": {"prod"}},
+				"Subnet":  {"Owner": {"network"}, "Environment": {"prod"}},
+				"Queue":   {},
+				"Self":    {},
+			},
+		},
+		{
+			name:      "stack tags are inherited by every resource",
+			stackTags: shared.TagMap{"Environment": {"dev"}},
+			expected: map[string]shared.TagMap{
+				"Network": {"Owner": {"platform"}, "Environment": {"dev"}},
+				"Remote":  {"Owner": {"platform"}, "Environment": {"dev"}},
+				"Bucket":  {"Environment": {"dev"}},
+				"Vpc":     {"Owner": {"platform"}, "Environment": {"dev"}},
+				"Subnets": {"Owner": {"platform"}, "Environment": {"prod"}},
+				"Subnet":  {"Owner": {"network"}, "Environment": {"prod"}},
+				"Queue":   {"Environment": {"dev"}},
+				"Self":    {"Environment": {"dev"}},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, resources, _ := ProcessDirectory(dir, rules, false, "", nil, nil, tc.stackTags)
+
+			actual := make(map[string]shared.TagMap)
+			var names []string
+			for _, r := range resources {
+				actual[r.ResourceName] = r.Tags
+				names = append(names, r.ResourceName)
+			}
+			if diff := cmp.Diff(tc.expected, actual); diff != "" {
+				t.Errorf("ProcessDirectory() tags mismatch (-want +got):\n%s", diff)
+			}
+			sort.Strings(names)
+			if len(names) != len(tc.expected) {
+				t.Errorf("ProcessDirectory() resources = %v; want each checked once", names)
+			}
+		})
+	}
+}
+
+func TestTemplateSetLoad(t *testing.T) {
+	dir := t.TempDir()
+	templatePath := filepath.Join(dir, "app.yaml")
+	if err := os.WriteFile(templatePath, []byte("Resources:\n  Bucket:\n    Type: AWS::S3::Bucket\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	templates := newTemplateSet()
+	file := templates.load(templatePath)
+	if file.root == nil || file.readErr != nil || file.parseErr != nil {
+		t.Fatalf("load() = %+v, want a parsed template", file)
+	}
+	if again := templates.load(filepath.Join(dir, ".", "app.yaml")); again != file {
+		t.Errorf("load() parsed the template again, want the same file")
+	}
+	if missing := templates.load(filepath.Join(dir, "missing.yaml")); missing.readErr == nil {
+		t.Errorf("load() of a missing file has no read error")
+	}
+}
+
+func TestSharedNestedStack(t *testing.T) {
+	parent := func(owner string) string {
+		return "Resources:\n  Child:\n    Type: AWS::CloudFormation::Stack\n    Properties:\n      TemplateURL: child.yaml\n" +
+			"      Tags:\n        - Key: Owner\n          Value: " + owner + "\n"
+	}
+	testCases := []struct {
+		name     string
+		owners   []string // the Owner tag of each parent's nested stack
+		expected int      // times the child's bucket is checked
+	}{
+		{name: "same inherited tags", owners: []string{"platform", "platform"}, expected: 1},
+		{name: "different inherited tags", owners: []string{"platform", "network"}, expected: 2},
+	}
+
+	rules := shared.Rules{RequiredTags: shared.TagMap{"Owner": {}}}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			dir := t.TempDir()
+			files := map[string]string{"child.yaml": "Resources:\n  Bucket:\n    Type: AWS::S3::Bucket\n"}
+			for i, owner := range tc.owners {
+				files[fmt.Sprintf("p%d.yaml", i+1)] = parent(owner)
+			}
+			for name, content := range files {
+				if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			_, resources, _ := ProcessDirectory(dir, rules, false, "", nil, nil, nil)
+			checked := 0
+			for _, r := range resources {
+				if r.ResourceName == "Bucket" {
+					checked++
+				}
+			}
+			if checked != tc.expected {
+				t.Errorf("ProcessDirectory() checked the shared child %d time(s); want %d", checked, tc.expected)
+			}
+		})
+	}
+}
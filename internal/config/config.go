@@ -6,10 +6,18 @@ import (
 )
 
 const (
-	TagNagIgnore       = "#tag-nag ignore"
-	TagNagIgnoreAll    = "#tag-nag ignore-all"
-	DefaultConfigFile  = ".tag-nag.yml"
-	AltConfigFile      = ".tag-nag.yaml"
+	TagNagIgnore           = "#tag-nag ignore"
+	TagNagIgnoreAll        = "#tag-nag ignore-all"
+	DefaultConfigFile      = ".tag-nag.yml"
+	AltConfigFile          = ".tag-nag.yaml"
+	DefaultMarkdownMaxSize = 65000 // under the GitHub comment limit of 65536 characters
+)
+
+// exit codes
+const (
+	ExitViolations = 1 // violations at or above --fail-on, or a threshold not met
+	ExitUsage      = 2 // invalid flags or config
+	ExitParseError = 3 // a scanned file could not be read or parsed, with --strict
 )
 
 var SkippedDirs = []string{
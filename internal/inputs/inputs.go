@@ -1,24 +1,33 @@
 package inputs
 
 import (
+	"errors"
 	"fmt"
 	"log"
 	"os"
+	"path"
+	"slices"
+	"sort"
 	"strings"
 
+	"github.com/jakebark/tag-nag/internal/config"
 	"github.com/jakebark/tag-nag/internal/shared"
 	"github.com/spf13/pflag"
 )
 
 type UserInput struct {
 	Directory       string
-	RequiredTags    shared.TagMap
+	Rules           shared.Rules
 	CaseInsensitive bool
 	DryRun          bool
 	CfnSpecPath     string
+	CfnInclude      []string
+	StackTags       shared.TagMap
 	Skip            []string
-	OutputFormat    shared.OutputFormat
-	OutputFile      string
+	Outputs         []shared.Output
+	FailOn          shared.Severity
+	Thresholds      shared.Thresholds
+	OutputOptions   shared.OutputOptions
 }
 
 // ParseFlags returns pased CLI flags and arguments
@@ -26,60 +35,137 @@ func ParseFlags() UserInput {
 	var caseInsensitive bool
 	var dryRun bool
 	var tags string
+	var keyCase string
+	var keyPrefixes string
 	var cfnSpecPath string
+	var cfnInclude string
+	var stackTags string
+	var templateConfig string
 	var skip string
-	var outputFormat string
+	var outputs []string
 	var outputFile string
+	var failOn string
+	var maxViolations int
+	var minCompliance float64
+	var requireIgnoreReason bool
+	var reportUnusedIgnores bool
+	var strict bool
+	var markdownMaxSize int
+	var checkstyleIncludeSkipped bool
+	var includeCompliant bool
+	var junitPerFile bool
+	var sortBy string
+	var templatePath string
 
 	pflag.BoolVarP(&caseInsensitive, "case-insensitive", "c", false, "Make tag checks non-case-sensitive")
 	pflag.BoolVarP(&dryRun, "dry-run", "d", false, "Dry run tag:nag without triggering exit(1) code")
 	pflag.StringVar(&tags, "tags", "", "Comma-separated list of required tag keys (e.g., 'Owner,Environment[Dev,Prod]')")
+	pflag.StringVar(&keyCase, "key-style", "", "Required tag key style: PascalCase, camelCase, kebab-case or snake_case")
+	pflag.StringVar(&keyPrefixes, "key-prefixes", "", "Comma-separated list of allowed tag key prefixes (e.g., 'acme' for 'acme:Owner')")
 	pflag.StringVar(&cfnSpecPath, "cfn-spec", "", "Optional path to CloudFormationResourceSpecification.json)")
+	pflag.StringVar(&cfnInclude, "cfn-include", "", "Comma-separated globs of CloudFormation templates to check, relative to the scanned directory (e.g., 'templates/**/*.yaml')")
+	pflag.StringVar(&stackTags, "stack-tags", "", "Comma-separated CloudFormation stack tags, inherited by every resource (e.g., 'Owner=platform,Environment=Prod')")
+	pflag.StringVar(&templateConfig, "template-config", "", "Path to a CloudFormation template configuration file, whose Tags are inherited by every resource")
 	pflag.StringVarP(&skip, "skip", "s", "", "Comma-separated list of files or directories to skip")
-	pflag.StringVarP(&outputFormat, "output", "o", "text", "Output format: text, json, junit-xml, or sarif")
-	pflag.StringVar(&outputFile, "output-file", "", "Write output to a file instead of stdout")
+	pflag.StringArrayVarP(&outputs, "output", "o", []string{"text"}, "Output format, optionally written to a file as format=path, repeatable: text, json, junit-xml, sarif, markdown, github, gitlab-codequality, checkstyle, csv, tsv, html or template")
+	pflag.StringVar(&templatePath, "template", "", "Path to a Go text/template file, for template output")
+	pflag.StringVar(&outputFile, "output-file", "", "Write a single output to a file instead of stdout")
+	pflag.StringVar(&sortBy, "sort-by", "file", "Order violations by file, type or tag")
+	pflag.StringVar(&failOn, "fail-on", "error", "Minimum severity that fails the run: error, warning or info")
+	pflag.IntVar(&maxViolations, "max-violations", -1, "Fail when there are more violations than this, -1 fails on any violation unless --min-compliance is set")
+	pflag.Float64Var(&minCompliance, "min-compliance", 0, "Fail when fewer than this percentage of resources are compliant (e.g., 95)")
+	pflag.BoolVar(&requireIgnoreReason, "require-ignore-reason", false, "Only honour ignore comments that give a reason=\"...\"")
+	pflag.BoolVar(&reportUnusedIgnores, "report-unused-ignores", false, "Report ignore comments that suppress nothing, or are not on a resource")
+	pflag.BoolVar(&strict, "strict", false, "Fail the run with exit code 3 when a file cannot be parsed, rather than reporting a warning")
+	pflag.IntVar(&markdownMaxSize, "markdown-max-size", config.DefaultMarkdownMaxSize, "Maximum size in bytes of markdown output, 0 for unlimited")
+	pflag.BoolVar(&checkstyleIncludeSkipped, "checkstyle-include-skipped", false, "Report skipped violations in checkstyle output at info severity, rather than leaving them out")
+	pflag.BoolVar(&includeCompliant, "include-compliant", false, "List compliant resources and their effective tags in csv and tsv output")
+	pflag.BoolVar(&junitPerFile, "junit-per-file", false, "Group junit-xml output into a suite per file, with passing resources as test cases")
 	pflag.Parse()
 
+	flagOutputOptions := shared.OutputOptions{
+		MarkdownMaxSize:          markdownMaxSize,
+		CheckstyleIncludeSkipped: checkstyleIncludeSkipped,
+		IncludeCompliant:         includeCompliant,
+		JUnitPerFile:             junitPerFile,
+		TemplatePath:             templatePath,
+		GitHubSummaryFile:        os.Getenv("GITHUB_STEP_SUMMARY"),
+	}
+
 	if pflag.NArg() < 1 {
-		log.Fatal("Error: specify a directory or file to scan")
+		usageErrorf("Error: specify a directory or file to scan")
 	}
 
 	// try config file if no tags provided
 	if tags == "" {
 		configFile, err := FindAndLoadConfigFile()
 		if err != nil {
-			log.Fatalf("Error loading config: %v", err)
+			usageErrorf("Error loading config: %v", err)
 		}
 		if configFile != nil {
-			// Use config output format if CLI wasn't specified
-			configOutputFormat := shared.OutputFormat(outputFormat)
-			outputFlag := pflag.Lookup("output")
-			if !outputFlag.Changed && configFile.Settings.Output != "" {
-				configOutputFormat = configFile.Settings.Output
+			configOutputs, err := resolveOutputs(outputs, outputFile, configFile)
+			if err != nil {
+				usageErrorf("Error parsing output: %v", err)
+			}
+			keyStyle, err := resolveKeyStyle(keyCase, keyPrefixes, configFile)
+			if err != nil {
+				usageErrorf("Error parsing key style: %v", err)
+			}
+			requiredTags := configFile.convertToTagMap()
+			severities, err := configFile.convertToSeverities()
+			if err != nil {
+				usageErrorf("Error parsing tag severity: %v", err)
+			}
+			failOnSeverity, err := resolveFailOn(failOn, configFile)
+			if err != nil {
+				usageErrorf("Error parsing fail-on: %v", err)
+			}
+			flagOutputOptions.FailOn = failOnSeverity
+			flagOutputOptions.SortBy, err = resolveSortBy(sortBy, configFile)
+			if err != nil {
+				usageErrorf("Error parsing sort-by: %v", err)
+			}
+			thresholds, err := resolveThresholds(maxViolations, minCompliance, configFile)
+			if err != nil {
+				usageErrorf("Error parsing thresholds: %v", err)
+			}
+			resolvedStackTags, err := resolveStackTags(stackTags, templateConfig, configFile)
+			if err != nil {
+				usageErrorf("Error parsing stack tags: %v", err)
 			}
-			// Use config output file if CLI wasn't specified
-			configOutputFile := outputFile
-			outputFileFlag := pflag.Lookup("output-file")
-			if !outputFileFlag.Changed && configFile.Settings.OutputFile != "" {
-				configOutputFile = configFile.Settings.OutputFile
+			resolvedCfnInclude, err := resolveCfnInclude(cfnInclude, configFile)
+			if err != nil {
+				usageErrorf("Error parsing cfn include: %v", err)
 			}
 			return UserInput{
-				Directory:       pflag.Arg(0),
-				RequiredTags:    configFile.convertToTagMap(),
+				Directory: pflag.Arg(0),
+				Rules: shared.Rules{
+					RequiredTags:        requiredTags,
+					Severities:          severities,
+					KeyStyle:            keyStyle,
+					FailOn:              failOnSeverity,
+					RequireIgnoreReason: requireIgnoreReason || configFile.Settings.RequireIgnoreReason,
+					ReportUnusedIgnores: reportUnusedIgnores || configFile.Settings.ReportUnusedIgnores,
+					Strict:              strict || configFile.Settings.Strict,
+				},
 				CaseInsensitive: configFile.Settings.CaseInsensitive,
 				DryRun:          configFile.Settings.DryRun,
 				CfnSpecPath:     configFile.Settings.CfnSpec,
+				CfnInclude:      resolvedCfnInclude,
+				StackTags:       resolvedStackTags,
 				Skip:            configFile.Skip,
-				OutputFormat:    configOutputFormat,
-				OutputFile:      configOutputFile,
+				Outputs:         configOutputs,
+				FailOn:          failOnSeverity,
+				Thresholds:      thresholds,
+				OutputOptions:   resolveOutputOptions(flagOutputOptions, requiredTags, severities, configFile),
 			}
 		}
-		log.Fatal("Error: specify required tags using --tags or create a .tag-nag.yml config file")
+		usageErrorf("Error: specify required tags using --tags or create a .tag-nag.yml config file")
 	}
 
 	parsedTags, err := parseTags(tags)
 	if err != nil {
-		log.Fatalf("Error parsing tags: %v", err)
+		usageErrorf("Error parsing tags: %v", err)
 	}
 
 	var skipPaths []string
@@ -93,36 +179,74 @@ func ParseFlags() UserInput {
 	// Try to load config file for output format default
 	configFile, err := FindAndLoadConfigFile()
 	if err != nil && !os.IsNotExist(err) {
-		log.Fatalf("Error loading config: %v", err)
+		usageErrorf("Error loading config: %v", err)
 	}
 
-	format := shared.OutputFormat(outputFormat)
-	// Use config output format if CLI wasn't explicitly provided and config exists
-	outputFlag := pflag.Lookup("output")
-	if !outputFlag.Changed && configFile != nil && configFile.Settings.Output != "" {
-		format = configFile.Settings.Output
+	keyStyle, err := resolveKeyStyle(keyCase, keyPrefixes, configFile)
+	if err != nil {
+		usageErrorf("Error parsing key style: %v", err)
 	}
 
-	if format != shared.OutputFormatText && format != shared.OutputFormatJSON && format != shared.OutputFormatJUnitXML && format != shared.OutputFormatSARIF {
-		log.Fatalf("Invalid output format '%s'. Supported formats: text, json, junit-xml, sarif", outputFormat)
+	failOnSeverity, err := resolveFailOn(failOn, configFile)
+	if err != nil {
+		usageErrorf("Error parsing fail-on: %v", err)
 	}
+	flagOutputOptions.FailOn = failOnSeverity
 
-	// Use config output file if CLI wasn't explicitly provided and config exists
-	resolvedOutputFile := outputFile
-	outputFileFlag := pflag.Lookup("output-file")
-	if !outputFileFlag.Changed && configFile != nil && configFile.Settings.OutputFile != "" {
-		resolvedOutputFile = configFile.Settings.OutputFile
+	flagOutputOptions.SortBy, err = resolveSortBy(sortBy, configFile)
+	if err != nil {
+		usageErrorf("Error parsing sort-by: %v", err)
+	}
+
+	resolvedOutputs, err := resolveOutputs(outputs, outputFile, configFile)
+	if err != nil {
+		usageErrorf("Error parsing output: %v", err)
+	}
+
+	thresholds, err := resolveThresholds(maxViolations, minCompliance, configFile)
+	if err != nil {
+		usageErrorf("Error parsing thresholds: %v", err)
+	}
+
+	resolvedStackTags, err := resolveStackTags(stackTags, templateConfig, configFile)
+	if err != nil {
+		usageErrorf("Error parsing stack tags: %v", err)
+	}
+	resolvedCfnInclude, err := resolveCfnInclude(cfnInclude, configFile)
+	if err != nil {
+		usageErrorf("Error parsing cfn include: %v", err)
+	}
+
+	if configFile != nil && configFile.Settings.RequireIgnoreReason {
+		requireIgnoreReason = true
+	}
+	if configFile != nil && configFile.Settings.ReportUnusedIgnores {
+		reportUnusedIgnores = true
+	}
+	if configFile != nil && configFile.Settings.Strict {
+		strict = true
 	}
 
 	return UserInput{
-		Directory:       pflag.Arg(0),
-		RequiredTags:    parsedTags,
+		Directory: pflag.Arg(0),
+		Rules: shared.Rules{
+			RequiredTags:        parsedTags,
+			KeyStyle:            keyStyle,
+			FailOn:              failOnSeverity,
+			RequireIgnoreReason: requireIgnoreReason,
+			ReportUnusedIgnores: reportUnusedIgnores,
+			Strict:              strict,
+		},
 		CaseInsensitive: caseInsensitive,
 		DryRun:          dryRun,
 		CfnSpecPath:     cfnSpecPath,
+		CfnInclude:      resolvedCfnInclude,
+		StackTags:       resolvedStackTags,
 		Skip:            skipPaths,
-		OutputFormat:    format,
-		OutputFile:      resolvedOutputFile,
+		Outputs:         resolvedOutputs,
+		FailOn:          failOnSeverity,
+		Thresholds:      thresholds,
+		OutputOptions:   resolveOutputOptions(flagOutputOptions, parsedTags, nil, configFile),
 	}
 }
 
@@ -186,6 +310,241 @@ func parseTag(tagComponent string) (key string, values []string, err error) {
 	return trimmed, []string{}, nil
 }
 
+// resolveKeyStyle builds the key style from CLI flags, falling back to the config file
+func resolveKeyStyle(keyCase string, keyPrefixes string, configFile *Config) (shared.KeyStyle, error) {
+	var prefixes []string
+	if keyPrefixes != "" {
+		for _, prefix := range strings.Split(keyPrefixes, ",") {
+			if trimmed := strings.TrimSpace(prefix); trimmed != "" {
+				prefixes = append(prefixes, trimmed)
+			}
+		}
+	}
+
+	if configFile != nil {
+		if keyCase == "" {
+			keyCase = configFile.KeyStyle.Case
+		}
+		if len(prefixes) == 0 {
+			prefixes = configFile.KeyStyle.Prefixes
+		}
+	}
+
+	keyStyle := shared.KeyStyle{Prefixes: prefixes}
+	if configFile != nil && configFile.KeyStyle.Severity != "" {
+		severity, ok := shared.ParseSeverity(configFile.KeyStyle.Severity)
+		if !ok {
+			return shared.KeyStyle{}, fmt.Errorf("invalid key style severity '%s'. Supported severities: error, warning, info", configFile.KeyStyle.Severity)
+		}
+		keyStyle.Severity = severity
+	}
+	if keyCase != "" {
+		parsedCase, ok := shared.ParseKeyCase(keyCase)
+		if !ok {
+			return shared.KeyStyle{}, fmt.Errorf("invalid key style '%s'. Supported styles: PascalCase, camelCase, kebab-case, snake_case", keyCase)
+		}
+		keyStyle.Case = parsedCase
+	}
+	return keyStyle, nil
+}
+
+// resolveFailOn returns the fail-on severity from the CLI flag, falling back to the config file
+func resolveFailOn(failOn string, configFile *Config) (shared.Severity, error) {
+	failOnFlag := pflag.Lookup("fail-on")
+	if (failOnFlag == nil || !failOnFlag.Changed) && configFile != nil && configFile.Settings.FailOn != "" {
+		failOn = configFile.Settings.FailOn
+	}
+
+	severity, ok := shared.ParseSeverity(failOn)
+	if !ok {
+		return "", fmt.Errorf("invalid severity '%s'. Supported severities: error, warning, info", failOn)
+	}
+	return severity, nil
+}
+
+// resolveSortBy returns the sort order from the CLI flag, falling back to the config file
+func resolveSortBy(sortBy string, configFile *Config) (shared.SortOrder, error) {
+	sortByFlag := pflag.Lookup("sort-by")
+	if (sortByFlag == nil || !sortByFlag.Changed) && configFile != nil && configFile.Settings.SortBy != "" {
+		sortBy = configFile.Settings.SortBy
+	}
+
+	sortOrder := shared.SortOrder(sortBy)
+	if !slices.Contains(shared.SortOrders, sortOrder) {
+		return "", fmt.Errorf("invalid sort order '%s'. Supported orders: file, type, tag", sortBy)
+	}
+	return sortOrder, nil
+}
+
+// resolveThresholds returns the fail thresholds from CLI flags, falling back to the config file.
+// With no maximum set, any violation fails the run, unless a minimum compliance is set instead
+func resolveThresholds(maxViolations int, minCompliance float64, configFile *Config) (shared.Thresholds, error) {
+	maxViolationsFlag := pflag.Lookup("max-violations")
+	if (maxViolationsFlag == nil || !maxViolationsFlag.Changed) && configFile != nil && configFile.Settings.MaxViolations != nil {
+		maxViolations = *configFile.Settings.MaxViolations
+	}
+	minComplianceFlag := pflag.Lookup("min-compliance")
+	if (minComplianceFlag == nil || !minComplianceFlag.Changed) && configFile != nil && configFile.Settings.MinCompliance != nil {
+		minCompliance = *configFile.Settings.MinCompliance
+	}
+
+	if maxViolations < -1 {
+		return shared.Thresholds{}, fmt.Errorf("invalid max violations %d, must be -1 or more", maxViolations)
+	}
+	if minCompliance < 0 || minCompliance > 100 {
+		return shared.Thresholds{}, fmt.Errorf("invalid min compliance %g, must be a percentage from 0 to 100", minCompliance)
+	}
+
+	if maxViolations == -1 && minCompliance == 0 {
+		maxViolations = 0
+	}
+	return shared.Thresholds{MaxViolations: maxViolations, MinCompliance: minCompliance}, nil
+}
+
+// resolveCfnInclude returns the template include globs from the CLI flag, falling back to the config file.
+// A malformed glob is an error, as it would otherwise never match
+func resolveCfnInclude(cfnInclude string, configFile *Config) ([]string, error) {
+	var include []string
+	for _, pattern := range strings.Split(cfnInclude, ",") {
+		if trimmed := strings.TrimSpace(pattern); trimmed != "" {
+			include = append(include, trimmed)
+		}
+	}
+	if len(include) == 0 && configFile != nil {
+		include = configFile.Settings.CfnInclude
+	}
+	for _, pattern := range include {
+		if _, err := path.Match(pattern, ""); errors.Is(err, path.ErrBadPattern) {
+			return nil, fmt.Errorf("invalid include glob '%s'", pattern)
+		}
+	}
+	return include, nil
+}
+
+// resolveStackTags returns the cfn stack tags, the template configuration file's tags with --stack-tags over them,
+// each falling back to the config file
+func resolveStackTags(stackTags string, templateConfig string, configFile *Config) (shared.TagMap, error) {
+	if templateConfig == "" && configFile != nil {
+		templateConfig = configFile.Settings.TemplateConfig
+	}
+	tags := make(shared.TagMap)
+	if templateConfig != "" {
+		configTags, err := loadTemplateConfigTags(templateConfig)
+		if err != nil {
+			return nil, err
+		}
+		for key, value := range configTags {
+			tags[key] = []string{value}
+		}
+	}
+
+	if stackTags == "" && configFile != nil {
+		for key, value := range configFile.Settings.StackTags {
+			tags[key] = []string{value}
+		}
+	}
+	for _, pair := range strings.Split(stackTags, ",") {
+		if strings.TrimSpace(pair) == "" {
+			continue
+		}
+		key, value, found := strings.Cut(pair, "=")
+		key = strings.TrimSpace(key)
+		if !found || key == "" {
+			return nil, fmt.Errorf("invalid stack tag '%s'. Expected Key=Value", strings.TrimSpace(pair))
+		}
+		tags[key] = []string{strings.TrimSpace(value)}
+	}
+
+	if len(tags) == 0 {
+		return nil, nil
+	}
+	return tags, nil
+}
+
+// resolveOutputs returns the reports to write from the CLI flags, falling back to the config file.
+// Each flag is format[=path], and --output-file gives the path of a single output
+func resolveOutputs(outputs []string, outputFile string, configFile *Config) ([]shared.Output, error) {
+	var resolved []shared.Output
+	outputFlag := pflag.Lookup("output")
+	if (outputFlag == nil || !outputFlag.Changed) && configFile != nil && len(configFile.Settings.Outputs) > 0 {
+		resolved = append(resolved, configFile.Settings.Outputs...)
+	} else if (outputFlag == nil || !outputFlag.Changed) && configFile != nil && configFile.Settings.Output != "" {
+		resolved = []shared.Output{{Format: configFile.Settings.Output}}
+	} else {
+		for _, output := range outputs {
+			format, file, _ := strings.Cut(output, "=")
+			resolved = append(resolved, shared.Output{Format: shared.OutputFormat(strings.TrimSpace(format)), File: strings.TrimSpace(file)})
+		}
+	}
+
+	outputFileFlag := pflag.Lookup("output-file")
+	if (outputFileFlag == nil || !outputFileFlag.Changed) && configFile != nil && configFile.Settings.OutputFile != "" {
+		outputFile = configFile.Settings.OutputFile
+	}
+	if outputFile != "" {
+		if len(resolved) != 1 || resolved[0].File != "" {
+			return nil, fmt.Errorf("output file '%s' only applies to a single output, use format=path for each output instead", outputFile)
+		}
+		resolved[0].File = outputFile
+	}
+
+	stdout := 0
+	files := make(map[string]bool)
+	for _, output := range resolved {
+		if !slices.Contains(shared.OutputFormats, output.Format) {
+			return nil, fmt.Errorf("invalid output format '%s'. Supported formats: text, json, junit-xml, sarif, markdown, github, gitlab-codequality, checkstyle, csv, tsv, html, template", output.Format)
+		}
+		if output.File == "" {
+			stdout++
+			continue
+		}
+		if files[output.File] {
+			return nil, fmt.Errorf("more than one output writes to '%s'", output.File)
+		}
+		files[output.File] = true
+	}
+	if stdout > 1 {
+		return nil, fmt.Errorf("only one output can write to stdout, give the others a path as format=path")
+	}
+	return resolved, nil
+}
+
+// resolveOutputOptions returns formatter settings from CLI flags, falling back to the config file
+func resolveOutputOptions(options shared.OutputOptions, requiredTags shared.TagMap, severities map[string]shared.Severity, configFile *Config) shared.OutputOptions {
+	for key := range requiredTags {
+		options.RequiredTags = append(options.RequiredTags, key)
+	}
+	sort.Strings(options.RequiredTags)
+	options.Severities = severities
+
+	if configFile == nil {
+		return options
+	}
+	markdownMaxSizeFlag := pflag.Lookup("markdown-max-size")
+	if (markdownMaxSizeFlag == nil || !markdownMaxSizeFlag.Changed) && configFile.Settings.MarkdownMaxSize != nil {
+		options.MarkdownMaxSize = *configFile.Settings.MarkdownMaxSize
+	}
+	if configFile.Settings.CheckstyleIncludeSkipped {
+		options.CheckstyleIncludeSkipped = true
+	}
+	if configFile.Settings.IncludeCompliant {
+		options.IncludeCompliant = true
+	}
+	if configFile.Settings.JUnitPerFile {
+		options.JUnitPerFile = true
+	}
+	if options.TemplatePath == "" {
+		options.TemplatePath = configFile.Settings.Template
+	}
+	return options
+}
+
+// usageErrorf logs an invalid flag or config, and exits with the usage exit code
+func usageErrorf(format string, args ...any) {
+	log.Printf(format, args...)
+	os.Exit(config.ExitUsage)
+}
+
 // splitTags splits the input string on commas outside of brackets
 // to fix the [a,b,c] issue
 func splitTags(input string) []string {
@@ -1,6 +1,8 @@
 package inputs
 
 import (
+	"os"
+	"path/filepath"
 	"reflect"
 	"testing"
 
@@ -159,3 +161,455 @@ func TestSplitTags(t *testing.T) {
 		})
 	}
 }
+
+func TestResolveKeyStyle(t *testing.T) {
+	configFile := &Config{KeyStyle: KeyStyleDefinition{Case: "kebab-case", Prefixes: []string{"acme"}}}
+
+	testCases := []struct {
+		name          string
+		keyCase       string
+		keyPrefixes   string
+		configFile    *Config
+		expected      shared.KeyStyle
+		expectedError bool
+	}{
+		{
+			name:     "no key style",
+			expected: shared.KeyStyle{},
+		},
+		{
+			name:        "flags",
+			keyCase:     "pascalcase",
+			keyPrefixes: "acme, team",
+			expected:    shared.KeyStyle{Case: "PascalCase", Prefixes: []string{"acme", "team"}},
+		},
+		{
+			name:       "config file",
+			configFile: configFile,
+			expected:   shared.KeyStyle{Case: "kebab-case", Prefixes: []string{"acme"}},
+		},
+		{
+			name:       "flags override config file",
+			keyCase:    "snake_case",
+			configFile: configFile,
+			expected:   shared.KeyStyle{Case: "snake_case", Prefixes: []string{"acme"}},
+		},
+		{
+			name:          "invalid key style",
+			keyCase:       "Title Case",
+			expectedError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			actual, err := resolveKeyStyle(tc.keyCase, tc.keyPrefixes, tc.configFile)
+			if tc.expectedError {
+				if err == nil {
+					t.Errorf("resolveKeyStyle(%q, %q) expected an error, but got nil", tc.keyCase, tc.keyPrefixes)
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("resolveKeyStyle(%q, %q) expected no error, but got: %v", tc.keyCase, tc.keyPrefixes, err)
+			}
+			if !reflect.DeepEqual(actual, tc.expected) {
+				t.Errorf("resolveKeyStyle(%q, %q) = %+v; want %+v", tc.keyCase, tc.keyPrefixes, actual, tc.expected)
+			}
+		})
+	}
+}
+
+func TestResolveFailOn(t *testing.T) {
+	testCases := []struct {
+		name          string
+		failOn        string
+		configFile    *Config
+		expected      shared.Severity
+		expectedError bool
+	}{
+		{
+			name:     "default",
+			failOn:   "error",
+			expected: shared.SeverityError,
+		},
+		{
+			name:     "flag",
+			failOn:   "warning",
+			expected: shared.SeverityWarning,
+		},
+		{
+			name:       "config file",
+			failOn:     "error",
+			configFile: &Config{Settings: Settings{FailOn: "info"}},
+			expected:   shared.SeverityInfo,
+		},
+		{
+			name:          "invalid severity",
+			failOn:        "critical",
+			expectedError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			actual, err := resolveFailOn(tc.failOn, tc.configFile)
+			if tc.expectedError {
+				if err == nil {
+					t.Errorf("resolveFailOn(%q) expected an error, but got nil", tc.failOn)
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("resolveFailOn(%q) expected no error, but got: %v", tc.failOn, err)
+			}
+			if actual != tc.expected {
+				t.Errorf("resolveFailOn(%q) = %q; want %q", tc.failOn, actual, tc.expected)
+			}
+		})
+	}
+}
+
+func TestResolveSortBy(t *testing.T) {
+	testCases := []struct {
+		name          string
+		sortBy        string
+		configFile    *Config
+		expected      shared.SortOrder
+		expectedError bool
+	}{
+		{
+			name:     "default",
+			sortBy:   "file",
+			expected: shared.SortByFile,
+		},
+		{
+			name:     "flag",
+			sortBy:   "tag",
+			expected: shared.SortByTag,
+		},
+		{
+			name:       "config file",
+			sortBy:     "file",
+			configFile: &Config{Settings: Settings{SortBy: "type"}},
+			expected:   shared.SortByType,
+		},
+		{
+			name:          "invalid order",
+			sortBy:        "severity",
+			expectedError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			actual, err := resolveSortBy(tc.sortBy, tc.configFile)
+			if tc.expectedError {
+				if err == nil {
+					t.Errorf("resolveSortBy(%q) expected an error, but got nil", tc.sortBy)
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("resolveSortBy(%q) expected no error, but got: %v", tc.sortBy, err)
+			}
+			if actual != tc.expected {
+				t.Errorf("resolveSortBy(%q) = %q; want %q", tc.sortBy, actual, tc.expected)
+			}
+		})
+	}
+}
+
+func TestResolveOutputs(t *testing.T) {
+	testCases := []struct {
+		name          string
+		outputs       []string
+		outputFile    string
+		configFile    *Config
+		expected      []shared.Output
+		expectedError bool
+	}{
+		{
+			name:     "default",
+			outputs:  []string{"text"},
+			expected: []shared.Output{{Format: shared.OutputFormatText}},
+		},
+		{
+			name:    "multiple outputs",
+			outputs: []string{"text", "sarif=tag-nag.sarif", "junit-xml=tag-nag.xml"},
+			expected: []shared.Output{
+				{Format: shared.OutputFormatText},
+				{Format: shared.OutputFormatSARIF, File: "tag-nag.sarif"},
+				{Format: shared.OutputFormatJUnitXML, File: "tag-nag.xml"},
+			},
+		},
+		{
+			name:       "output file",
+			outputs:    []string{"json"},
+			outputFile: "report.json",
+			expected:   []shared.Output{{Format: shared.OutputFormatJSON, File: "report.json"}},
+		},
+		{
+			name:       "config outputs",
+			outputs:    []string{"text"},
+			configFile: &Config{Settings: Settings{Outputs: []shared.Output{{Format: shared.OutputFormatText}, {Format: shared.OutputFormatCSV, File: "tags.csv"}}}},
+			expected:   []shared.Output{{Format: shared.OutputFormatText}, {Format: shared.OutputFormatCSV, File: "tags.csv"}},
+		},
+		{
+			name:       "config output and output file",
+			outputs:    []string{"text"},
+			configFile: &Config{Settings: Settings{Output: shared.OutputFormatHTML, OutputFile: "report.html"}},
+			expected:   []shared.Output{{Format: shared.OutputFormatHTML, File: "report.html"}},
+		},
+		{
+			name:          "invalid format",
+			outputs:       []string{"text", "yaml=report.yaml"},
+			expectedError: true,
+		},
+		{
+			name:          "two stdout outputs",
+			outputs:       []string{"text", "json"},
+			expectedError: true,
+		},
+		{
+			name:          "same file twice",
+			outputs:       []string{"json=report", "csv=report"},
+			expectedError: true,
+		},
+		{
+			name:          "output file with multiple outputs",
+			outputs:       []string{"text", "json"},
+			outputFile:    "report.json",
+			expectedError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			actual, err := resolveOutputs(tc.outputs, tc.outputFile, tc.configFile)
+			if tc.expectedError {
+				if err == nil {
+					t.Errorf("resolveOutputs(%q) expected an error, but got nil", tc.outputs)
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("resolveOutputs(%q) expected no error, but got: %v", tc.outputs, err)
+			}
+			if !reflect.DeepEqual(actual, tc.expected) {
+				t.Errorf("resolveOutputs(%q) = %+v; want %+v", tc.outputs, actual, tc.expected)
+			}
+		})
+	}
+}
+
+func TestResolveThresholds(t *testing.T) {
+	maxViolations := 5
+	minCompliance := 90.0
+
+	testCases := []struct {
+		name          string
+		maxViolations int
+		minCompliance float64
+		configFile    *Config
+		expected      shared.Thresholds
+		expectedError bool
+	}{
+		{
+			name:          "default fails on any violation",
+			maxViolations: -1,
+			expected:      shared.Thresholds{MaxViolations: 0},
+		},
+		{
+			name:          "max violations",
+			maxViolations: 10,
+			expected:      shared.Thresholds{MaxViolations: 10},
+		},
+		{
+			name:          "min compliance only",
+			maxViolations: -1,
+			minCompliance: 95,
+			expected:      shared.Thresholds{MaxViolations: -1, MinCompliance: 95},
+		},
+		{
+			name:          "both",
+			maxViolations: 3,
+			minCompliance: 95,
+			expected:      shared.Thresholds{MaxViolations: 3, MinCompliance: 95},
+		},
+		{
+			name:          "config file",
+			maxViolations: -1,
+			configFile:    &Config{Settings: Settings{MaxViolations: &maxViolations, MinCompliance: &minCompliance}},
+			expected:      shared.Thresholds{MaxViolations: 5, MinCompliance: 90},
+		},
+		{
+			name:          "invalid max violations",
+			maxViolations: -2,
+			expectedError: true,
+		},
+		{
+			name:          "invalid min compliance",
+			maxViolations: -1,
+			minCompliance: 101,
+			expectedError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			actual, err := resolveThresholds(tc.maxViolations, tc.minCompliance, tc.configFile)
+			if tc.expectedError {
+				if err == nil {
+					t.Errorf("resolveThresholds(%d, %g) expected an error, but got nil", tc.maxViolations, tc.minCompliance)
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("resolveThresholds(%d, %g) expected no error, but got: %v", tc.maxViolations, tc.minCompliance, err)
+			}
+			if actual != tc.expected {
+				t.Errorf("resolveThresholds(%d, %g) = %+v; want %+v", tc.maxViolations, tc.minCompliance, actual, tc.expected)
+			}
+		})
+	}
+}
+
+func TestResolveCfnInclude(t *testing.T) {
+	testCases := []struct {
+		name          string
+		cfnInclude    string
+		configFile    *Config
+		expected      []string
+		expectedError bool
+	}{
+		{
+			name:     "none",
+			expected: nil,
+		},
+		{
+			name:       "flag",
+			cfnInclude: "templates/**/*.yaml, stacks/*.json",
+			expected:   []string{"templates/**/*.yaml", "stacks/*.json"},
+		},
+		{
+			name:       "config file",
+			configFile: &Config{Settings: Settings{CfnInclude: []string{"templates/**"}}},
+			expected:   []string{"templates/**"},
+		},
+		{
+			name:       "flag overrides config file",
+			cfnInclude: "stacks/*.json",
+			configFile: &Config{Settings: Settings{CfnInclude: []string{"templates/**"}}},
+			expected:   []string{"stacks/*.json"},
+		},
+		{
+			name:          "invalid flag glob",
+			cfnInclude:    "templates/[.yaml",
+			expectedError: true,
+		},
+		{
+			name:          "invalid config file glob",
+			configFile:    &Config{Settings: Settings{CfnInclude: []string{"templates/\\"}}},
+			expectedError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			actual, err := resolveCfnInclude(tc.cfnInclude, tc.configFile)
+			if tc.expectedError {
+				if err == nil {
+					t.Errorf("resolveCfnInclude(%q) expected an error, but got nil", tc.cfnInclude)
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("resolveCfnInclude(%q) expected no error, but got: %v", tc.cfnInclude, err)
+			}
+			if !reflect.DeepEqual(actual, tc.expected) {
+				t.Errorf("resolveCfnInclude(%q) = %v; want %v", tc.cfnInclude, actual, tc.expected)
+			}
+		})
+	}
+}
+
+func TestResolveStackTags(t *testing.T) {
+	templateConfig := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(templateConfig, []byte(`{"Parameters": {"Env": "prod"}, "Tags": {"Owner": "platform", "Environment": "prod"}}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		name           string
+		stackTags      string
+		templateConfig string
+		configFile     *Config
+		expected       shared.TagMap
+		expectedErr    bool
+	}{
+		{
+			name:     "none",
+			expected: nil,
+		},
+		{
+			name:      "flag",
+			stackTags: "Owner=platform, Environment = prod",
+			expected:  shared.TagMap{"Owner": {"platform"}, "Environment": {"prod"}},
+		},
+		{
+			name:      "empty value",
+			stackTags: "Owner=",
+			expected:  shared.TagMap{"Owner": {""}},
+		},
+		{
+			name:           "template config",
+			templateConfig: templateConfig,
+			expected:       shared.TagMap{"Owner": {"platform"}, "Environment": {"prod"}},
+		},
+		{
+			name:           "flag over template config",
+			stackTags:      "Environment=dev",
+			templateConfig: templateConfig,
+			expected:       shared.TagMap{"Owner": {"platform"}, "Environment": {"dev"}},
+		},
+		{
+			name:       "config file",
+			configFile: &Config{Settings: Settings{StackTags: map[string]string{"Owner": "data"}, TemplateConfig: templateConfig}},
+			expected:   shared.TagMap{"Owner": {"data"}, "Environment": {"prod"}},
+		},
+		{
+			name:       "flag overrides config file",
+			stackTags:  "Project=web",
+			configFile: &Config{Settings: Settings{StackTags: map[string]string{"Owner": "data"}}},
+			expected:   shared.TagMap{"Project": {"web"}},
+		},
+		{
+			name:        "missing value separator",
+			stackTags:   "Owner",
+			expectedErr: true,
+		},
+		{
+			name:        "empty key",
+			stackTags:   "=platform",
+			expectedErr: true,
+		},
+		{
+			name:           "missing template config",
+			templateConfig: filepath.Join(t.TempDir(), "missing.json"),
+			expectedErr:    true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			actual, err := resolveStackTags(tc.stackTags, tc.templateConfig, tc.configFile)
+			if (err != nil) != tc.expectedErr {
+				t.Fatalf("resolveStackTags() error = %v, expectedErr %v", err, tc.expectedErr)
+			}
+			if !reflect.DeepEqual(actual, tc.expected) {
+				t.Errorf("resolveStackTags() = %v; want %v", actual, tc.expected)
+			}
+		})
+	}
+}
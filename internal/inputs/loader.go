@@ -1,6 +1,7 @@
 package inputs
 
 import (
+	"encoding/json"
 	"fmt"
 	"os"
 
@@ -10,22 +11,46 @@ import (
 )
 
 type Config struct {
-	Tags     []TagDefinition `yaml:"tags"`
-	Settings Settings        `yaml:"settings"`
-	Skip     []string        `yaml:"skip"`
+	Tags     []TagDefinition    `yaml:"tags"`
+	KeyStyle KeyStyleDefinition `yaml:"key_style"`
+	Settings Settings           `yaml:"settings"`
+	Skip     []string           `yaml:"skip"`
 }
 
 type TagDefinition struct {
-	Key    string   `yaml:"key"`
-	Values []string `yaml:"values,omitempty"`
+	Key      string   `yaml:"key"`
+	Values   []string `yaml:"values,omitempty"`
+	Severity string   `yaml:"severity,omitempty"`
+}
+
+type KeyStyleDefinition struct {
+	Case     string   `yaml:"case"`
+	Prefixes []string `yaml:"prefixes,omitempty"`
+	Severity string   `yaml:"severity,omitempty"`
 }
 
 type Settings struct {
-	CaseInsensitive bool                `yaml:"case_insensitive"`
-	DryRun          bool                `yaml:"dry_run"`
-	CfnSpec         string              `yaml:"cfn_spec"`
-	Output          shared.OutputFormat `yaml:"output"`
-	OutputFile      string              `yaml:"output_file"`
+	CaseInsensitive          bool                `yaml:"case_insensitive"`
+	DryRun                   bool                `yaml:"dry_run"`
+	CfnSpec                  string              `yaml:"cfn_spec"`
+	CfnInclude               []string            `yaml:"cfn_include,omitempty"` // globs of templates to check, all templates when empty
+	StackTags                map[string]string   `yaml:"stack_tags,omitempty"`  // inherited by every cfn resource
+	TemplateConfig           string              `yaml:"template_config"`       // cfn template configuration file, whose tags are inherited
+	Output                   shared.OutputFormat `yaml:"output"`
+	OutputFile               string              `yaml:"output_file"`
+	Outputs                  []shared.Output     `yaml:"outputs"`
+	FailOn                   string              `yaml:"fail_on"`
+	MaxViolations            *int                `yaml:"max_violations,omitempty"` // nil fails on any violation, unless min_compliance is set
+	MinCompliance            *float64            `yaml:"min_compliance,omitempty"` // nil has no minimum
+	SortBy                   string              `yaml:"sort_by"`
+	RequireIgnoreReason      bool                `yaml:"require_ignore_reason"`
+	ReportUnusedIgnores      bool                `yaml:"report_unused_ignores"`
+	Strict                   bool                `yaml:"strict"`
+	MarkdownMaxSize          *int                `yaml:"markdown_max_size,omitempty"` // nil uses the default
+	CheckstyleIncludeSkipped bool                `yaml:"checkstyle_include_skipped"`
+	IncludeCompliant         bool                `yaml:"include_compliant"`
+	JUnitPerFile             bool                `yaml:"junit_per_file"`
+	Template                 string              `yaml:"template"`
 }
 
 // FindAndLoadConfigFile attempts to find and load configuration file
@@ -66,3 +91,37 @@ func (c *Config) convertToTagMap() shared.TagMap {
 
 	return tagMap
 }
+
+// convertToSeverities returns the severity of each config tag that sets one
+func (c *Config) convertToSeverities() (map[string]shared.Severity, error) {
+	severities := make(map[string]shared.Severity)
+
+	for _, tag := range c.Tags {
+		if tag.Severity == "" {
+			continue
+		}
+		severity, ok := shared.ParseSeverity(tag.Severity)
+		if !ok {
+			return nil, fmt.Errorf("invalid severity '%s' for tag %s. Supported severities: error, warning, info", tag.Severity, tag.Key)
+		}
+		severities[tag.Key] = severity
+	}
+
+	return severities, nil
+}
+
+// loadTemplateConfigTags reads the tags of a cfn template configuration file, eg {"Parameters": {...}, "Tags": {"Owner": "platform"}}
+func loadTemplateConfigTags(path string) (map[string]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading template configuration file %s: %w", path, err)
+	}
+
+	var templateConfig struct {
+		Tags map[string]string `json:"Tags"`
+	}
+	if err := json.Unmarshal(data, &templateConfig); err != nil {
+		return nil, fmt.Errorf("parsing template configuration file %s: %w", path, err)
+	}
+	return templateConfig.Tags, nil
+}
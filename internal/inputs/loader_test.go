@@ -126,6 +126,33 @@ func TestProcessConfigFile(t *testing.T) {
 			configFile:    "../../testdata/config/tag_array.yml",
 			expectedError: true,
 		},
+		{
+			name:              "severity",
+			configFile:        "../../testdata/config/severity.yml",
+			expectedError:     false,
+			expectedTags:      3,
+			expectedOwner:     true,
+			expectedEnvValues: []string{"Dev", "Test", "Prod"},
+			expectedSettings: Settings{
+				FailOn: "warning",
+			},
+			expectedSkips: []string{},
+		},
+		{
+			name:          "outputs",
+			configFile:    "../../testdata/config/outputs.yml",
+			expectedError: false,
+			expectedTags:  1,
+			expectedOwner: true,
+			expectedSettings: Settings{
+				Outputs: []shared.Output{
+					{Format: shared.OutputFormatText},
+					{Format: shared.OutputFormatSARIF, File: "tag-nag.sarif"},
+					{Format: shared.OutputFormatJUnitXML, File: "tag-nag.xml"},
+				},
+			},
+			expectedSkips: []string{},
+		},
 		{
 			name:          "no file",
 			configFile:    "../../testdata/config/does-not-exist.yml",
@@ -184,7 +211,7 @@ func TestProcessConfigFile(t *testing.T) {
 			}
 
 			// Check settings
-			if config.Settings != tc.expectedSettings {
+			if !reflect.DeepEqual(config.Settings, tc.expectedSettings) {
 				t.Errorf("Expected settings %+v, got %+v", tc.expectedSettings, config.Settings)
 			}
 
@@ -424,3 +451,51 @@ func TestConvertToTagMap(t *testing.T) {
 		})
 	}
 }
+
+func TestConvertToSeverities(t *testing.T) {
+	testCases := []struct {
+		name          string
+		configFile    string
+		expected      map[string]shared.Severity
+		expectedError bool
+	}{
+		{
+			name:       "severity",
+			configFile: "../../testdata/config/severity.yml",
+			expected:   map[string]shared.Severity{"CostCenter": shared.SeverityWarning},
+		},
+		{
+			name:       "no severity",
+			configFile: "../../testdata/config/tag_values.yml",
+			expected:   map[string]shared.Severity{},
+		},
+		{
+			name:          "invalid severity",
+			configFile:    "../../testdata/config/invalid_severity.yml",
+			expectedError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			config, err := processConfigFile(tc.configFile)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			result, err := config.convertToSeverities()
+			if tc.expectedError {
+				if err == nil {
+					t.Errorf("Expected error but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("Unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(result, tc.expected) {
+				t.Errorf("Expected severities %v, got %v", tc.expected, result)
+			}
+		})
+	}
+}
@@ -0,0 +1,75 @@
+package output
+
+import (
+	"encoding/xml"
+	"fmt"
+	"sort"
+
+	"github.com/jakebark/tag-nag/internal/shared"
+)
+
+// CheckstyleFormatter implements Checkstyle XML, for Jenkins Warnings NG, reviewdog and similar
+type CheckstyleFormatter struct {
+	IncludeSkipped bool // report ignored findings at info severity, rather than leaving them out
+}
+
+type Checkstyle struct {
+	XMLName xml.Name         `xml:"checkstyle"`
+	Version string           `xml:"version,attr"`
+	Files   []CheckstyleFile `xml:"file"`
+}
+
+type CheckstyleFile struct {
+	Name   string            `xml:"name,attr"`
+	Errors []CheckstyleError `xml:"error"`
+}
+
+type CheckstyleError struct {
+	Line     int    `xml:"line,attr"`
+	Severity string `xml:"severity,attr"`
+	Message  string `xml:"message,attr"`
+	Source   string `xml:"source,attr"`
+}
+
+// Format formats violations as Checkstyle XML, one error per finding, grouped by file
+func (f *CheckstyleFormatter) Format(violations []shared.Violation, resources []shared.Resource) ([]byte, error) {
+	fileErrors := make(map[string][]CheckstyleError)
+
+	for _, v := range violations {
+		if v.Skip && !f.IncludeSkipped {
+			continue
+		}
+		for _, finding := range v.Findings {
+			severity := string(finding.Severity)
+			if v.Skip || finding.Suppressed {
+				if !f.IncludeSkipped {
+					continue
+				}
+				severity = "info"
+			}
+			if severity == "" {
+				severity = string(shared.SeverityError)
+			}
+			fileErrors[v.FilePath] = append(fileErrors[v.FilePath], CheckstyleError{
+				Line:     v.FindingLine(finding),
+				Severity: severity,
+				Message:  fmt.Sprintf("%s: %s", describeResource(v), describeFinding(finding)),
+				Source:   "tag-nag." + finding.RuleID,
+			})
+		}
+	}
+
+	checkstyle := Checkstyle{Version: "4.3"}
+	for filePath, errors := range fileErrors {
+		sort.SliceStable(errors, func(i, j int) bool { return errors[i].Line < errors[j].Line })
+		checkstyle.Files = append(checkstyle.Files, CheckstyleFile{Name: filePath, Errors: errors})
+	}
+	sort.Slice(checkstyle.Files, func(i, j int) bool { return checkstyle.Files[i].Name < checkstyle.Files[j].Name })
+
+	output, err := xml.MarshalIndent(checkstyle, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	return []byte(xml.Header + string(output)), nil
+}
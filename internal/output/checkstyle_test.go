@@ -0,0 +1,85 @@
+package output
+
+import (
+	"encoding/xml"
+	"testing"
+
+	"github.com/jakebark/tag-nag/internal/shared"
+)
+
+func TestCheckstyleFormatter_Format(t *testing.T) {
+	violations := []shared.Violation{
+		{ResourceType: "aws_s3_bucket", ResourceName: "b", Findings: missingTags("Owner"), FilePath: "z.tf", Line: 4},
+		{ResourceType: "aws_s3_bucket", ResourceName: "a", Findings: append(missingTags("Owner"), warningTags("CostCenter")...), FilePath: "main.tf", Line: 10},
+		{ResourceType: "aws_instance", ResourceName: "old", Findings: []shared.Finding{{RuleID: shared.RuleMissingTag, Tag: "Owner", Severity: shared.SeverityError, Suppressed: true}}, FilePath: "main.tf", Line: 2, Skip: true},
+	}
+
+	testCases := []struct {
+		name           string
+		includeSkipped bool
+		wantFiles      []string
+		wantErrors     map[string][]CheckstyleError
+	}{
+		{
+			name:      "skipped left out",
+			wantFiles: []string{"main.tf", "z.tf"},
+			wantErrors: map[string][]CheckstyleError{
+				"main.tf": {
+					{Line: 10, Severity: "error", Message: `aws_s3_bucket "a": Owner`, Source: "tag-nag.missing-tag"},
+					{Line: 10, Severity: "warning", Message: `aws_s3_bucket "a": CostCenter [warning]`, Source: "tag-nag.missing-tag"},
+				},
+				"z.tf": {
+					{Line: 4, Severity: "error", Message: `aws_s3_bucket "b": Owner`, Source: "tag-nag.missing-tag"},
+				},
+			},
+		},
+		{
+			name:           "skipped at info",
+			includeSkipped: true,
+			wantFiles:      []string{"main.tf", "z.tf"},
+			wantErrors: map[string][]CheckstyleError{
+				"main.tf": {
+					{Line: 2, Severity: "info", Message: `aws_instance "old": Owner (ignored)`, Source: "tag-nag.missing-tag"},
+					{Line: 10, Severity: "error", Message: `aws_s3_bucket "a": Owner`, Source: "tag-nag.missing-tag"},
+					{Line: 10, Severity: "warning", Message: `aws_s3_bucket "a": CostCenter [warning]`, Source: "tag-nag.missing-tag"},
+				},
+				"z.tf": {
+					{Line: 4, Severity: "error", Message: `aws_s3_bucket "b": Owner`, Source: "tag-nag.missing-tag"},
+				},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			formatter := &CheckstyleFormatter{IncludeSkipped: tc.includeSkipped}
+			output, err := formatter.Format(violations, nil)
+			if err != nil {
+				t.Fatalf("Format() error = %v", err)
+			}
+
+			var checkstyle Checkstyle
+			if err := xml.Unmarshal(output, &checkstyle); err != nil {
+				t.Fatalf("Output is not valid XML: %v", err)
+			}
+			if len(checkstyle.Files) != len(tc.wantFiles) {
+				t.Fatalf("Got %d files, want %d", len(checkstyle.Files), len(tc.wantFiles))
+			}
+			for i, file := range checkstyle.Files {
+				if file.Name != tc.wantFiles[i] {
+					t.Errorf("File %d = %s, want %s", i, file.Name, tc.wantFiles[i])
+				}
+				want := tc.wantErrors[file.Name]
+				if len(file.Errors) != len(want) {
+					t.Errorf("%s has %d errors, want %d: %+v", file.Name, len(file.Errors), len(want), file.Errors)
+					continue
+				}
+				for j := range want {
+					if file.Errors[j] != want[j] {
+						t.Errorf("%s error %d = %+v, want %+v", file.Name, j, file.Errors[j], want[j])
+					}
+				}
+			}
+		})
+	}
+}
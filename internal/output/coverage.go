@@ -0,0 +1,98 @@
+package output
+
+import (
+	"path"
+
+	"github.com/jakebark/tag-nag/internal/shared"
+)
+
+// Coverage measures how well evaluated resources are tagged
+type Coverage struct {
+	Resources   int                 `json:"resources"`
+	Compliant   int                 `json:"compliant"`
+	Percent     float64             `json:"percent"` // compliant resources, 100 when nothing was evaluated
+	Tags        []TagCoverage       `json:"tags"`
+	ByType      map[string]Coverage `json:"by_type,omitempty"`      // keyed by resource type
+	ByDirectory map[string]Coverage `json:"by_directory,omitempty"` // keyed by directory, relative to the working directory
+}
+
+// TagCoverage counts resources by the state of one required tag
+type TagCoverage struct {
+	Tag     string `json:"tag"`
+	Present int    `json:"present"`
+	Absent  int    `json:"absent"`
+	Invalid int    `json:"invalid"` // present with a disallowed value
+}
+
+// measureCoverage counts compliant resources, and the state of each required tag across them,
+// overall and by resource type and directory.
+// Ignored findings still count against coverage, as the tag is still missing.
+func measureCoverage(violations []shared.Violation, resources []shared.Resource, requiredTags []string) Coverage {
+	resourceFindings := make(map[string][]shared.Finding)
+	for _, v := range violations {
+		key := resourceKey(v.FilePath, v.ResourceType, v.ResourceName)
+		resourceFindings[key] = append(resourceFindings[key], v.Findings...)
+	}
+
+	coverage := countCoverage(resources, resourceFindings, requiredTags)
+	coverage.ByType = groupCoverage(resources, resourceFindings, requiredTags, func(r shared.Resource) string { return r.ResourceType })
+	coverage.ByDirectory = groupCoverage(resources, resourceFindings, requiredTags, func(r shared.Resource) string { return path.Dir(relativePath(r.FilePath)) })
+	return coverage
+}
+
+// groupCoverage measures coverage of the resources sharing each key, nil when there are none
+func groupCoverage(resources []shared.Resource, resourceFindings map[string][]shared.Finding, requiredTags []string, key func(shared.Resource) string) map[string]Coverage {
+	grouped := make(map[string][]shared.Resource)
+	for _, r := range resources {
+		grouped[key(r)] = append(grouped[key(r)], r)
+	}
+	if len(grouped) == 0 {
+		return nil
+	}
+
+	groups := make(map[string]Coverage, len(grouped))
+	for k, groupResources := range grouped {
+		groups[k] = countCoverage(groupResources, resourceFindings, requiredTags)
+	}
+	return groups
+}
+
+// countCoverage counts compliant resources, and the state of each required tag across them
+func countCoverage(resources []shared.Resource, resourceFindings map[string][]shared.Finding, requiredTags []string) Coverage {
+	coverage := Coverage{Resources: len(resources), Percent: 100}
+	for _, r := range resources {
+		if r.Compliant {
+			coverage.Compliant++
+		}
+	}
+	if coverage.Resources > 0 {
+		coverage.Percent = float64(coverage.Compliant) * 100 / float64(coverage.Resources)
+	}
+
+	for _, tag := range requiredTags {
+		tagCoverage := TagCoverage{Tag: tag}
+		for _, r := range resources {
+			switch tagState(resourceFindings[resourceKey(r.FilePath, r.ResourceType, r.ResourceName)], tag) {
+			case shared.RuleMissingTag:
+				tagCoverage.Absent++
+			case shared.RuleInvalidTagValue:
+				tagCoverage.Invalid++
+			default:
+				tagCoverage.Present++
+			}
+		}
+		coverage.Tags = append(coverage.Tags, tagCoverage)
+	}
+
+	return coverage
+}
+
+// tagState returns the rule a resource's findings break for a tag, or "" when the tag is present and valid
+func tagState(findings []shared.Finding, tag string) string {
+	for _, f := range findings {
+		if f.Tag == tag && (f.RuleID == shared.RuleMissingTag || f.RuleID == shared.RuleInvalidTagValue) {
+			return f.RuleID
+		}
+	}
+	return ""
+}
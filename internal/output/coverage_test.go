@@ -0,0 +1,82 @@
+package output
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/jakebark/tag-nag/internal/shared"
+)
+
+func TestMeasureCoverage(t *testing.T) {
+	violations := []shared.Violation{
+		{ResourceType: "aws_s3_bucket", ResourceName: "a", FilePath: "main.tf", Findings: missingTags("Owner")},
+		{ResourceType: "aws_s3_bucket", ResourceName: "b", FilePath: "main.tf", Skip: true, Findings: []shared.Finding{
+			{RuleID: shared.RuleMissingTag, Tag: "Owner", Suppressed: true},
+			{RuleID: shared.RuleInvalidTagValue, Tag: "Environment", Suppressed: true},
+		}},
+	}
+	resources := []shared.Resource{
+		{ResourceType: "aws_s3_bucket", ResourceName: "a", FilePath: "main.tf"},
+		{ResourceType: "aws_s3_bucket", ResourceName: "b", FilePath: "main.tf"},
+		{ResourceType: "aws_s3_bucket", ResourceName: "c", FilePath: "main.tf", Compliant: true},
+		{ResourceType: "aws_instance", ResourceName: "d", FilePath: "modules/vpc/main.tf", Compliant: true},
+	}
+	buckets := Coverage{
+		Resources: 3,
+		Compliant: 1,
+		Percent:   100.0 / 3,
+		Tags: []TagCoverage{
+			{Tag: "Environment", Present: 2, Invalid: 1},
+			{Tag: "Owner", Present: 1, Absent: 2},
+		},
+	}
+	instances := Coverage{
+		Resources: 1,
+		Compliant: 1,
+		Percent:   100,
+		Tags: []TagCoverage{
+			{Tag: "Environment", Present: 1},
+			{Tag: "Owner", Present: 1},
+		},
+	}
+
+	testCases := []struct {
+		name         string
+		violations   []shared.Violation
+		resources    []shared.Resource
+		requiredTags []string
+		expected     Coverage
+	}{
+		{
+			name:         "nothing evaluated",
+			requiredTags: []string{"Owner"},
+			expected:     Coverage{Percent: 100, Tags: []TagCoverage{{Tag: "Owner"}}},
+		},
+		{
+			name:         "ignored findings count against coverage",
+			violations:   violations,
+			resources:    resources,
+			requiredTags: []string{"Environment", "Owner"},
+			expected: Coverage{
+				Resources: 4,
+				Compliant: 2,
+				Percent:   50,
+				Tags: []TagCoverage{
+					{Tag: "Environment", Present: 3, Invalid: 1},
+					{Tag: "Owner", Present: 2, Absent: 2},
+				},
+				ByType:      map[string]Coverage{"aws_s3_bucket": buckets, "aws_instance": instances},
+				ByDirectory: map[string]Coverage{".": buckets, "modules/vpc": instances},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := measureCoverage(tc.violations, tc.resources, tc.requiredTags)
+			if diff := cmp.Diff(tc.expected, got); diff != "" {
+				t.Errorf("measureCoverage() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
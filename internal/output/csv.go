@@ -0,0 +1,114 @@
+package output
+
+import (
+	"bytes"
+	"encoding/csv"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/jakebark/tag-nag/internal/shared"
+)
+
+// CSVFormatter implements CSV and TSV output, for spreadsheets and tagging coverage exports
+type CSVFormatter struct {
+	IncludeCompliant bool // list compliant resources, with a column per effective tag
+	Comma            rune // field delimiter, eg '\t' for tsv, defaults to ','
+}
+
+var csvHeader = []string{"file", "line", "resource_type", "resource_name", "rule", "tag", "status"}
+
+// Format formats violations as CSV, one row per finding
+func (f *CSVFormatter) Format(violations []shared.Violation, resources []shared.Resource) ([]byte, error) {
+	var tagKeys []string
+	resourceTags := make(map[string]shared.TagMap)
+	if f.IncludeCompliant {
+		tagKeys, resourceTags = indexResourceTags(resources)
+	}
+
+	var rows [][]string
+	for _, v := range violations {
+		tags := resourceTags[resourceKey(v.FilePath, v.ResourceType, v.ResourceName)]
+		for _, finding := range v.Findings {
+			rows = append(rows, csvRow(v.FilePath, v.FindingLine(finding), v.ResourceType, v.ResourceName, finding.RuleID, finding.Tag, violationStatus(v, finding), tagKeys, tags))
+		}
+	}
+
+	if f.IncludeCompliant {
+		for _, r := range resources {
+			if r.Compliant {
+				rows = append(rows, csvRow(r.FilePath, r.Line, r.ResourceType, r.ResourceName, "", "", "compliant", tagKeys, r.Tags))
+			}
+		}
+	}
+
+	sort.SliceStable(rows, func(i, j int) bool {
+		if rows[i][0] != rows[j][0] {
+			return rows[i][0] < rows[j][0]
+		}
+		lineI, _ := strconv.Atoi(rows[i][1])
+		lineJ, _ := strconv.Atoi(rows[j][1])
+		return lineI < lineJ
+	})
+
+	header := append([]string(nil), csvHeader...)
+	for _, key := range tagKeys {
+		header = append(header, "tag:"+key)
+	}
+
+	var output bytes.Buffer
+	writer := csv.NewWriter(&output)
+	if f.Comma != 0 {
+		writer.Comma = f.Comma
+	}
+	if err := writer.Write(header); err != nil {
+		return nil, err
+	}
+	if err := writer.WriteAll(rows); err != nil {
+		return nil, err
+	}
+
+	return output.Bytes(), nil
+}
+
+// indexResourceTags returns every tag key in use, sorted, and the effective tags of each resource
+func indexResourceTags(resources []shared.Resource) ([]string, map[string]shared.TagMap) {
+	seen := make(map[string]bool)
+	var tagKeys []string
+	resourceTags := make(map[string]shared.TagMap)
+
+	for _, r := range resources {
+		resourceTags[resourceKey(r.FilePath, r.ResourceType, r.ResourceName)] = r.Tags
+		for key := range r.Tags {
+			if !seen[key] {
+				seen[key] = true
+				tagKeys = append(tagKeys, key)
+			}
+		}
+	}
+	sort.Strings(tagKeys)
+
+	return tagKeys, resourceTags
+}
+
+// resourceKey matches a violation to the resource it was raised on
+func resourceKey(filePath, resourceType, resourceName string) string {
+	return filePath + "\x00" + resourceType + "\x00" + resourceName
+}
+
+// violationStatus is "skipped" for ignored findings and violations, otherwise "violation"
+func violationStatus(v shared.Violation, finding shared.Finding) string {
+	if v.Skip || finding.Suppressed {
+		return "skipped"
+	}
+	return "violation"
+}
+
+// csvRow builds a row, with the resource's tag values when tag columns are included
+func csvRow(filePath string, line int, resourceType, resourceName, rule, tag, status string, tagKeys []string, tags shared.TagMap) []string {
+	row := []string{relativePath(filePath), strconv.Itoa(line), resourceType, resourceName, rule, tag, status}
+	for _, key := range tagKeys {
+		row = append(row, strings.Join(tags[key], ";"))
+	}
+	return row
+}
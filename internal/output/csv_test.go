@@ -0,0 +1,84 @@
+package output
+
+import (
+	"encoding/csv"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/jakebark/tag-nag/internal/shared"
+)
+
+func TestCSVFormatter_Format(t *testing.T) {
+	violations := []shared.Violation{
+		{ResourceType: "aws_s3_bucket", ResourceName: "b", Findings: missingTags("Owner", "Project"), FilePath: "z.tf", Line: 4},
+		{ResourceType: "aws_instance", ResourceName: "old", Findings: []shared.Finding{{RuleID: shared.RuleMissingTag, Tag: "Owner", Severity: shared.SeverityError, Suppressed: true}}, FilePath: "main.tf", Line: 2, Skip: true},
+	}
+	resources := []shared.Resource{
+		{ResourceType: "aws_instance", ResourceName: "old", Line: 2, FilePath: "main.tf", Tags: shared.TagMap{"Project": {"web"}}},
+		{ResourceType: "aws_s3_bucket", ResourceName: "a", Line: 10, FilePath: "main.tf", Tags: shared.TagMap{"Owner": {"jake"}, "Project": {"web"}}, Compliant: true},
+		{ResourceType: "aws_s3_bucket", ResourceName: "b", Line: 4, FilePath: "z.tf"},
+	}
+
+	testCases := []struct {
+		name             string
+		includeCompliant bool
+		expected         [][]string
+	}{
+		{
+			name: "violations only",
+			expected: [][]string{
+				{"file", "line", "resource_type", "resource_name", "rule", "tag", "status"},
+				{"main.tf", "2", "aws_instance", "old", "missing-tag", "Owner", "skipped"},
+				{"z.tf", "4", "aws_s3_bucket", "b", "missing-tag", "Owner", "violation"},
+				{"z.tf", "4", "aws_s3_bucket", "b", "missing-tag", "Project", "violation"},
+			},
+		},
+		{
+			name:             "include compliant",
+			includeCompliant: true,
+			expected: [][]string{
+				{"file", "line", "resource_type", "resource_name", "rule", "tag", "status", "tag:Owner", "tag:Project"},
+				{"main.tf", "2", "aws_instance", "old", "missing-tag", "Owner", "skipped", "", "web"},
+				{"main.tf", "10", "aws_s3_bucket", "a", "", "", "compliant", "jake", "web"},
+				{"z.tf", "4", "aws_s3_bucket", "b", "missing-tag", "Owner", "violation", "", ""},
+				{"z.tf", "4", "aws_s3_bucket", "b", "missing-tag", "Project", "violation", "", ""},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			formatter := &CSVFormatter{IncludeCompliant: tc.includeCompliant}
+			output, err := formatter.Format(violations, resources)
+			if err != nil {
+				t.Fatalf("Format() error = %v", err)
+			}
+
+			records, err := csv.NewReader(strings.NewReader(string(output))).ReadAll()
+			if err != nil {
+				t.Fatalf("Output is not valid CSV: %v", err)
+			}
+			if diff := cmp.Diff(tc.expected, records); diff != "" {
+				t.Errorf("Format() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestCSVFormatter_TSV(t *testing.T) {
+	violations := []shared.Violation{
+		{ResourceType: "aws_s3_bucket", ResourceName: "b", Findings: missingTags("Owner"), FilePath: "main.tf", Line: 4},
+	}
+
+	output, err := (&CSVFormatter{Comma: '\t'}).Format(violations, nil)
+	if err != nil {
+		t.Fatalf("Format() error = %v", err)
+	}
+
+	expected := "file\tline\tresource_type\tresource_name\trule\ttag\tstatus\n" +
+		"main.tf\t4\taws_s3_bucket\tb\tmissing-tag\tOwner\tviolation\n"
+	if diff := cmp.Diff(expected, string(output)); diff != "" {
+		t.Errorf("Format() mismatch (-want +got):\n%s", diff)
+	}
+}
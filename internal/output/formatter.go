@@ -1,24 +1,143 @@
 package output
 
-import "github.com/jakebark/tag-nag/internal/shared"
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/jakebark/tag-nag/internal/shared"
+)
 
 type Formatter interface {
-	Format(violations []shared.Violation) ([]byte, error)
+	Format(violations []shared.Violation, resources []shared.Resource) ([]byte, error)
 }
 
 // GetFormatter returns the appropriate formatter for the given format
-func GetFormatter(format shared.OutputFormat) Formatter {
+func GetFormatter(format shared.OutputFormat, options shared.OutputOptions) Formatter {
 	switch format {
 	case shared.OutputFormatJSON:
-		return &JSONFormatter{}
+		return &JSONFormatter{RequiredTags: options.RequiredTags}
 	case shared.OutputFormatJUnitXML:
-		return &JUnitXMLFormatter{}
+		return &JUnitXMLFormatter{PerFile: options.JUnitPerFile, FailOn: options.FailOn}
 	case shared.OutputFormatSARIF:
-		return &SARIFFormatter{}
+		return &SARIFFormatter{RequiredTags: options.RequiredTags, Severities: options.Severities}
+	case shared.OutputFormatMarkdown:
+		return &MarkdownFormatter{MaxSize: options.MarkdownMaxSize}
+	case shared.OutputFormatGitHub:
+		return &GitHubFormatter{}
+	case shared.OutputFormatGitLab:
+		return &GitLabFormatter{}
+	case shared.OutputFormatCheckstyle:
+		return &CheckstyleFormatter{IncludeSkipped: options.CheckstyleIncludeSkipped}
+	case shared.OutputFormatCSV:
+		return &CSVFormatter{IncludeCompliant: options.IncludeCompliant}
+	case shared.OutputFormatTSV:
+		return &CSVFormatter{IncludeCompliant: options.IncludeCompliant, Comma: '\t'}
+	case shared.OutputFormatHTML:
+		return &HTMLFormatter{RequiredTags: options.RequiredTags}
+	case shared.OutputFormatTemplate:
+		return &TemplateFormatter{Path: options.TemplatePath, RequiredTags: options.RequiredTags}
 	case shared.OutputFormatText:
 		fallthrough
 	default:
-		return &TextFormatter{}
+		return &TextFormatter{SortBy: options.SortBy, RequiredTags: options.RequiredTags}
+	}
+}
+
+// relativePath returns a path relative to the working directory, with forward slashes for links
+func relativePath(path string) string {
+	if filepath.IsAbs(path) {
+		if wd, err := os.Getwd(); err == nil {
+			if rel, err := filepath.Rel(wd, path); err == nil && !strings.HasPrefix(rel, "..") {
+				path = rel
+			}
+		}
+	}
+	return filepath.ToSlash(path)
+}
+
+// describeResource names the resource of a violation, eg `aws_s3_bucket "this"`, with its cdk construct path when known, or "file" for file-level violations
+func describeResource(v shared.Violation) string {
+	if v.ResourceType == "" {
+		return "file"
 	}
+	if v.Construct != "" {
+		return fmt.Sprintf("%s %q (%s)", v.ResourceType, v.ResourceName, v.Construct)
+	}
+	return fmt.Sprintf("%s %q", v.ResourceType, v.ResourceName)
+}
+
+// resourceAddress is a dotted resource identifier, eg "aws_s3_bucket.this", or "file" for file-level violations
+func resourceAddress(v shared.Violation) string {
+	if v.ResourceType == "" {
+		return "file"
+	}
+	return fmt.Sprintf("%s.%s", v.ResourceType, v.ResourceName)
 }
 
+// describeViolation summarises the findings on a violation, eg `Missing tags: Owner; Invalid values: Env[prod] (found "dev")`
+func describeViolation(v shared.Violation) string {
+	var missingTags, invalidValues, keyIssues, ignoreIssues, parseErrors []string
+	for _, f := range v.Findings {
+		switch {
+		case f.RuleID == shared.RuleParseError:
+			parseErrors = append(parseErrors, describeFinding(f))
+		case f.RuleID == shared.RuleInvalidTagValue:
+			invalidValues = append(invalidValues, describeFinding(f))
+		case f.IsTagRule():
+			missingTags = append(missingTags, describeFinding(f))
+		case f.IsKeyRule():
+			keyIssues = append(keyIssues, describeFinding(f))
+		default:
+			ignoreIssues = append(ignoreIssues, describeFinding(f))
+		}
+	}
+
+	var parts []string
+	if len(parseErrors) > 0 {
+		parts = append(parts, "Parse error: "+strings.Join(parseErrors, ", "))
+	}
+	if len(missingTags) > 0 {
+		parts = append(parts, "Missing tags: "+strings.Join(missingTags, ", "))
+	}
+	if len(invalidValues) > 0 {
+		parts = append(parts, "Invalid values: "+strings.Join(invalidValues, ", "))
+	}
+	if len(keyIssues) > 0 {
+		parts = append(parts, "Key issues: "+strings.Join(keyIssues, ", "))
+	}
+	if len(ignoreIssues) > 0 {
+		parts = append(parts, "Ignore issues: "+strings.Join(ignoreIssues, ", "))
+	}
+	return strings.Join(parts, "; ")
+}
+
+// describeFinding adds the value found to a finding, eg `Env[Prod] (found "Dev" in default_tags)`
+func describeFinding(f shared.Finding) string {
+	description := f.String()
+	if f.RuleID == shared.RuleInvalidTagValue {
+		source := ""
+		if f.InheritedFrom != "" {
+			source = " in " + f.InheritedFrom
+		}
+		description += fmt.Sprintf(" (found %q%s)", f.Actual, source)
+	} else if f.InheritedFrom != "" {
+		description += fmt.Sprintf(" (in %s)", f.InheritedFrom)
+	}
+	if f.Severity != "" && f.Severity != shared.SeverityError {
+		description += fmt.Sprintf(" [%s]", f.Severity)
+	}
+	if f.Suppressed {
+		description += " " + describeIgnored(f.Reason)
+	}
+	return description
+}
+
+// describeIgnored notes an ignore comment and its reason, eg `(ignored: "shared infra")`
+func describeIgnored(reason string) string {
+	if reason == "" {
+		return "(ignored)"
+	}
+	return fmt.Sprintf("(ignored: %q)", reason)
+}
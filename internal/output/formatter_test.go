@@ -28,6 +28,46 @@ func TestGetFormatter(t *testing.T) {
 			format:       shared.OutputFormatSARIF,
 			expectedType: "*output.SARIFFormatter",
 		},
+		{
+			name:         "markdown format",
+			format:       shared.OutputFormatMarkdown,
+			expectedType: "*output.MarkdownFormatter",
+		},
+		{
+			name:         "github format",
+			format:       shared.OutputFormatGitHub,
+			expectedType: "*output.GitHubFormatter",
+		},
+		{
+			name:         "gitlab-codequality format",
+			format:       shared.OutputFormatGitLab,
+			expectedType: "*output.GitLabFormatter",
+		},
+		{
+			name:         "checkstyle format",
+			format:       shared.OutputFormatCheckstyle,
+			expectedType: "*output.CheckstyleFormatter",
+		},
+		{
+			name:         "csv format",
+			format:       shared.OutputFormatCSV,
+			expectedType: "*output.CSVFormatter",
+		},
+		{
+			name:         "tsv format",
+			format:       shared.OutputFormatTSV,
+			expectedType: "*output.CSVFormatter",
+		},
+		{
+			name:         "html format",
+			format:       shared.OutputFormatHTML,
+			expectedType: "*output.HTMLFormatter",
+		},
+		{
+			name:         "template format",
+			format:       shared.OutputFormatTemplate,
+			expectedType: "*output.TemplateFormatter",
+		},
 		{
 			name:         "text format",
 			format:       shared.OutputFormatText,
@@ -42,7 +82,7 @@ func TestGetFormatter(t *testing.T) {
 
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
-			formatter := GetFormatter(tc.format)
+			formatter := GetFormatter(tc.format, shared.OutputOptions{})
 			actualType := reflect.TypeOf(formatter).String()
 			if actualType != tc.expectedType {
 				t.Errorf("GetFormatter(%q) = %s; want %s", tc.format, actualType, tc.expectedType)
@@ -51,3 +91,20 @@ func TestGetFormatter(t *testing.T) {
 	}
 }
 
+// missingTags builds missing-tag findings for formatter tests
+func missingTags(tags ...string) []shared.Finding {
+	var findings []shared.Finding
+	for _, tag := range tags {
+		findings = append(findings, shared.Finding{RuleID: shared.RuleMissingTag, Tag: tag, Severity: shared.SeverityError})
+	}
+	return findings
+}
+
+// warningTags builds missing-tag findings with warning severity for formatter tests
+func warningTags(tags ...string) []shared.Finding {
+	findings := missingTags(tags...)
+	for i := range findings {
+		findings[i].Severity = shared.SeverityWarning
+	}
+	return findings
+}
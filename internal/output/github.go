@@ -0,0 +1,107 @@
+package output
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/jakebark/tag-nag/internal/shared"
+)
+
+// githubSummaryMaxSize is under the 1MiB limit for a job summary
+const githubSummaryMaxSize = 1000000
+
+// GitHubFormatter implements GitHub Actions workflow commands, for annotations on pull requests
+type GitHubFormatter struct{}
+
+// Format formats violations as workflow commands, eg ::error file=main.tf,line=3,title=tag-nag::...
+func (f *GitHubFormatter) Format(violations []shared.Violation, resources []shared.Resource) ([]byte, error) {
+	var output strings.Builder
+
+	sorted := append([]shared.Violation(nil), violations...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].FilePath != sorted[j].FilePath {
+			return sorted[i].FilePath < sorted[j].FilePath
+		}
+		return sorted[i].Line < sorted[j].Line
+	})
+
+	for _, v := range sorted {
+		command := githubCommand(v)
+		message := fmt.Sprintf("%s: %s", describeResource(v), describeViolation(v))
+		if v.Skip {
+			message = fmt.Sprintf("%s: skipped", describeResource(v))
+			if v.SkipReason != "" {
+				message += " " + describeIgnored(v.SkipReason)
+			}
+		}
+		output.WriteString(fmt.Sprintf("::%s file=%s,line=%d,title=tag-nag::%s\n",
+			command, escapeGitHubProperty(relativePath(v.FilePath)), annotationLine(v), escapeGitHubData(message)))
+	}
+
+	return []byte(output.String()), nil
+}
+
+// writeJobSummary appends a markdown report to the job summary file, eg $GITHUB_STEP_SUMMARY
+func writeJobSummary(summaryFile string, violations []shared.Violation, resources []shared.Resource) error {
+	summary, err := (&MarkdownFormatter{MaxSize: githubSummaryMaxSize}).Format(violations, resources)
+	if err != nil {
+		return err
+	}
+
+	file, err := os.OpenFile(summaryFile, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	_, err = file.Write(summary)
+	return err
+}
+
+// githubCommand maps a violation to an annotation level
+func githubCommand(v shared.Violation) string {
+	if v.Skip {
+		return "notice"
+	}
+	switch v.Severity() {
+	case shared.SeverityWarning:
+		return "warning"
+	case shared.SeverityInfo:
+		return "notice"
+	default:
+		return "error"
+	}
+}
+
+// escapeGitHubData escapes a workflow command message
+func escapeGitHubData(text string) string {
+	text = strings.ReplaceAll(text, "%", "%25")
+	text = strings.ReplaceAll(text, "\r", "%0D")
+	return strings.ReplaceAll(text, "\n", "%0A")
+}
+
+// escapeGitHubProperty escapes a workflow command property, eg file=
+func escapeGitHubProperty(text string) string {
+	text = escapeGitHubData(text)
+	text = strings.ReplaceAll(text, ":", "%3A")
+	return strings.ReplaceAll(text, ",", "%2C")
+}
+
+// annotationLine returns the line to annotate, the findings' line when they all share one, eg a bad tag value,
+// otherwise the resource's line
+func annotationLine(v shared.Violation) int {
+	line := 0
+	for _, finding := range v.Findings {
+		findingLine := v.FindingLine(finding)
+		if line != 0 && findingLine != line {
+			return v.Line
+		}
+		line = findingLine
+	}
+	if line == 0 {
+		return v.Line
+	}
+	return line
+}
@@ -0,0 +1,109 @@
+package output
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jakebark/tag-nag/internal/shared"
+)
+
+func TestGitHubFormatter_Format(t *testing.T) {
+	testCases := []struct {
+		name       string
+		violations []shared.Violation
+		wantLines  []string
+	}{
+		{
+			name:       "empty violations",
+			violations: []shared.Violation{},
+			wantLines:  nil,
+		},
+		{
+			name: "error, warning and skipped",
+			violations: []shared.Violation{
+				{ResourceType: "aws_s3_bucket", ResourceName: "test", Findings: missingTags("Owner"), FilePath: "main.tf", Line: 10},
+				{ResourceType: "aws_instance", ResourceName: "web", Findings: warningTags("CostCenter"), FilePath: "main.tf", Line: 20},
+				{ResourceType: "aws_instance", ResourceName: "old", FilePath: "main.tf", Line: 30, Skip: true, SkipReason: "legacy"},
+			},
+			wantLines: []string{
+				`::error file=main.tf,line=10,title=tag-nag::aws_s3_bucket "test": Missing tags: Owner`,
+				`::warning file=main.tf,line=20,title=tag-nag::aws_instance "web": Missing tags: CostCenter [warning]`,
+				`::notice file=main.tf,line=30,title=tag-nag::aws_instance "old": skipped (ignored: "legacy")`,
+			},
+		},
+		{
+			name: "finding lines",
+			violations: []shared.Violation{
+				{ResourceType: "AWS::S3::Bucket", ResourceName: "one", Findings: []shared.Finding{
+					{RuleID: shared.RuleInvalidTagValue, Tag: "Env", Expected: []string{"dev"}, Actual: "prod", Severity: shared.SeverityError, Line: 9},
+				}, FilePath: "template.yaml", Line: 3},
+				{ResourceType: "AWS::S3::Bucket", ResourceName: "two", Findings: []shared.Finding{
+					{RuleID: shared.RuleMissingTag, Tag: "Owner", Severity: shared.SeverityError},
+					{RuleID: shared.RuleInvalidTagValue, Tag: "Env", Expected: []string{"dev"}, Actual: "prod", Severity: shared.SeverityError, Line: 19},
+				}, FilePath: "template.yaml", Line: 12},
+			},
+			wantLines: []string{
+				`::error file=template.yaml,line=9,title=tag-nag::AWS::S3::Bucket "one": Invalid values: Env[dev] (found "prod")`,
+				`::error file=template.yaml,line=12,title=tag-nag::AWS::S3::Bucket "two": Missing tags: Owner; Invalid values: Env[dev] (found "prod")`,
+			},
+		},
+		{
+			name: "escaping",
+			violations: []shared.Violation{
+				{ResourceType: "aws_s3_bucket", ResourceName: "test", Findings: missingTags("Owner"), FilePath: "a,b:c.tf", Line: 1, Skip: true, SkipReason: "100%\nsure"},
+			},
+			wantLines: []string{
+				`::notice file=a%2Cb%3Ac.tf,line=1,title=tag-nag::aws_s3_bucket "test": skipped (ignored: "100%25\nsure")`,
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			formatter := &GitHubFormatter{}
+			output, err := formatter.Format(tc.violations, nil)
+			if err != nil {
+				t.Fatalf("Format() error = %v", err)
+			}
+
+			var lines []string
+			if len(output) > 0 {
+				lines = strings.Split(strings.TrimSuffix(string(output), "\n"), "\n")
+			}
+			if len(lines) != len(tc.wantLines) {
+				t.Fatalf("Format() = %d lines, want %d:\n%s", len(lines), len(tc.wantLines), output)
+			}
+			for i, want := range tc.wantLines {
+				if lines[i] != want {
+					t.Errorf("line %d = %q, want %q", i, lines[i], want)
+				}
+			}
+		})
+	}
+}
+
+func TestWriteJobSummary(t *testing.T) {
+	summaryFile := filepath.Join(t.TempDir(), "summary.md")
+	if err := os.WriteFile(summaryFile, []byte("previous step\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	violations := []shared.Violation{
+		{ResourceType: "aws_s3_bucket", ResourceName: "test", Findings: missingTags("Owner"), FilePath: "main.tf", Line: 10},
+	}
+	if err := writeJobSummary(summaryFile, violations, nil); err != nil {
+		t.Fatalf("writeJobSummary() error = %v", err)
+	}
+
+	summary, err := os.ReadFile(summaryFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"previous step\n", "## tag-nag report", "`aws_s3_bucket.test`"} {
+		if !strings.Contains(string(summary), want) {
+			t.Errorf("job summary missing %q", want)
+		}
+	}
+}
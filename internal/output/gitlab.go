@@ -0,0 +1,84 @@
+package output
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+
+	"github.com/jakebark/tag-nag/internal/shared"
+)
+
+// GitLabFormatter implements the GitLab Code Quality report format
+type GitLabFormatter struct{}
+
+// GitLabIssue is a single code quality finding
+type GitLabIssue struct {
+	Description string         `json:"description"`
+	CheckName   string         `json:"check_name"`
+	Fingerprint string         `json:"fingerprint"`
+	Severity    string         `json:"severity"`
+	Location    GitLabLocation `json:"location"`
+}
+
+type GitLabLocation struct {
+	Path  string      `json:"path"`
+	Lines GitLabLines `json:"lines"`
+}
+
+type GitLabLines struct {
+	Begin int `json:"begin"`
+}
+
+// Format formats violations as a code quality report, one issue per finding
+// suppressed findings are left out, GitLab has no way to show them
+func (f *GitLabFormatter) Format(violations []shared.Violation, resources []shared.Resource) ([]byte, error) {
+	issues := []GitLabIssue{}
+
+	for _, v := range violations {
+		path := relativePath(v.FilePath)
+		for _, finding := range v.Findings {
+			if finding.Suppressed {
+				continue
+			}
+			issues = append(issues, GitLabIssue{
+				Description: fmt.Sprintf("%s: %s", describeResource(v), describeFinding(finding)),
+				CheckName:   finding.RuleID,
+				Fingerprint: findingFingerprint(v, finding),
+				Severity:    gitlabSeverity(finding.Severity),
+				Location: GitLabLocation{
+					Path:  path,
+					Lines: GitLabLines{Begin: v.FindingLine(finding)},
+				},
+			})
+		}
+	}
+
+	return json.MarshalIndent(issues, "", "  ")
+}
+
+// findingFingerprint identifies a finding across commits and merge requests, so it leaves out the line number
+// file-level findings, eg ignore comments, have no resource to tell them apart, so their line is kept
+func findingFingerprint(v shared.Violation, finding shared.Finding) string {
+	key := relativePath(v.FilePath) + "\x00" + resourceAddress(v) + "\x00" + finding.RuleID + "\x00" + finding.Tag
+	if finding.Message != "" {
+		key += "\x00" + finding.Message // several parse errors in a file
+	}
+	if v.ResourceType == "" {
+		key += fmt.Sprintf("\x00%d", v.FindingLine(finding))
+	}
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// gitlabSeverity maps a severity to a code quality severity
+func gitlabSeverity(severity shared.Severity) string {
+	switch severity {
+	case shared.SeverityWarning:
+		return "minor"
+	case shared.SeverityInfo:
+		return "info"
+	default:
+		return "major"
+	}
+}
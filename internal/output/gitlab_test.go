@@ -0,0 +1,82 @@
+package output
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/jakebark/tag-nag/internal/shared"
+)
+
+func TestGitLabFormatter_Format(t *testing.T) {
+	violations := []shared.Violation{
+		{ResourceType: "aws_s3_bucket", ResourceName: "test", Findings: append(missingTags("Owner"), warningTags("CostCenter")...), FilePath: "main.tf", Line: 10},
+		{ResourceType: "aws_instance", ResourceName: "web", Findings: []shared.Finding{{RuleID: shared.RuleMissingTag, Tag: "Owner", Severity: shared.SeverityError, Suppressed: true}}, FilePath: "main.tf", Line: 20, Skip: true},
+	}
+
+	formatter := &GitLabFormatter{}
+	output, err := formatter.Format(violations, nil)
+	if err != nil {
+		t.Fatalf("Format() error = %v", err)
+	}
+
+	var issues []GitLabIssue
+	if err := json.Unmarshal(output, &issues); err != nil {
+		t.Fatalf("Output is not valid JSON: %v", err)
+	}
+	if len(issues) != 2 {
+		t.Fatalf("Format() = %d issues, want 2 (suppressed findings left out)", len(issues))
+	}
+
+	owner := issues[0]
+	if owner.CheckName != shared.RuleMissingTag || owner.Severity != "major" || owner.Location.Path != "main.tf" || owner.Location.Lines.Begin != 10 {
+		t.Errorf("Unexpected issue %+v", owner)
+	}
+	if owner.Description != `aws_s3_bucket "test": Owner` {
+		t.Errorf("Description = %q", owner.Description)
+	}
+	if issues[1].Severity != "minor" {
+		t.Errorf("Warning severity = %q, want minor", issues[1].Severity)
+	}
+	if owner.Fingerprint == issues[1].Fingerprint {
+		t.Error("Findings for different tags should have different fingerprints")
+	}
+}
+
+func TestGitLabFormatter_EmptyIsArray(t *testing.T) {
+	output, err := (&GitLabFormatter{}).Format(nil, nil)
+	if err != nil {
+		t.Fatalf("Format() error = %v", err)
+	}
+	if string(output) != "[]" {
+		t.Errorf("Format(nil) = %s, want []", output)
+	}
+}
+
+func TestGitLabFingerprint(t *testing.T) {
+	finding := shared.Finding{RuleID: shared.RuleMissingTag, Tag: "Owner"}
+	violation := shared.Violation{ResourceType: "aws_s3_bucket", ResourceName: "test", FilePath: "main.tf", Line: 1}
+	fingerprint := findingFingerprint(violation, finding)
+
+	moved := violation
+	moved.Line = 10
+	if fingerprint != findingFingerprint(moved, finding) {
+		t.Error("Fingerprint should be stable when the resource moves")
+	}
+	otherFile := violation
+	otherFile.FilePath = "other.tf"
+	if fingerprint == findingFingerprint(otherFile, finding) {
+		t.Error("Fingerprint should depend on the file")
+	}
+	otherResource := violation
+	otherResource.ResourceName = "other"
+	if fingerprint == findingFingerprint(otherResource, finding) {
+		t.Error("Fingerprint should depend on the resource address")
+	}
+
+	unattached := shared.Finding{RuleID: shared.RuleUnattachedIgnore}
+	first := shared.Violation{FilePath: "main.tf", Line: 3}
+	second := shared.Violation{FilePath: "main.tf", Line: 12}
+	if findingFingerprint(first, unattached) == findingFingerprint(second, unattached) {
+		t.Error("Fingerprint should depend on the line of file-level findings")
+	}
+}
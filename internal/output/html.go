@@ -0,0 +1,109 @@
+package output
+
+import (
+	"bytes"
+	_ "embed"
+	"fmt"
+	"html/template"
+	"sort"
+
+	"github.com/jakebark/tag-nag/internal/shared"
+)
+
+//go:embed html.tmpl
+var htmlTemplate string
+
+// HTMLFormatter implements a self-contained HTML report, for publishing as a CI artifact
+type HTMLFormatter struct {
+	RequiredTags []string
+}
+
+// htmlReport is the data rendered by html.tmpl
+type htmlReport struct {
+	Summary  Summary
+	Coverage Coverage
+	Rows     []htmlRow
+	Files    []htmlFile
+}
+
+type htmlRow struct {
+	File     string
+	Line     int
+	Resource string
+	Issues   string
+	Status   string // violation, warning, info or skipped
+}
+
+type htmlFile struct {
+	Path string
+	Rows []htmlRow
+}
+
+// Format formats violations as a single HTML page, with embedded CSS and JS and no network fetches
+func (f *HTMLFormatter) Format(violations []shared.Violation, resources []shared.Resource) ([]byte, error) {
+	tmpl, err := template.New("report").Funcs(template.FuncMap{
+		"percent": func(count, total int) string {
+			if total == 0 {
+				return "100"
+			}
+			return fmt.Sprintf("%.1f", float64(count)*100/float64(total))
+		},
+	}).Parse(htmlTemplate)
+	if err != nil {
+		return nil, err
+	}
+
+	report := htmlReport{
+		Summary:  summarize(violations),
+		Coverage: measureCoverage(violations, resources, f.RequiredTags),
+	}
+
+	fileRows := make(map[string][]htmlRow)
+	for _, v := range violations {
+		row := htmlRow{
+			File:     relativePath(v.FilePath),
+			Line:     v.Line,
+			Resource: resourceAddress(v),
+			Issues:   describeViolation(v),
+			Status:   htmlStatus(v),
+		}
+		if v.Skip {
+			row.Issues = "skipped"
+			if v.SkipReason != "" {
+				row.Issues += " " + describeIgnored(v.SkipReason)
+			}
+		}
+		fileRows[row.File] = append(fileRows[row.File], row)
+	}
+
+	for path, rows := range fileRows {
+		sort.SliceStable(rows, func(i, j int) bool { return rows[i].Line < rows[j].Line })
+		report.Files = append(report.Files, htmlFile{Path: path, Rows: rows})
+	}
+	sort.Slice(report.Files, func(i, j int) bool { return report.Files[i].Path < report.Files[j].Path })
+	for _, file := range report.Files {
+		report.Rows = append(report.Rows, file.Rows...)
+	}
+
+	var output bytes.Buffer
+	if err := tmpl.Execute(&output, report); err != nil {
+		return nil, err
+	}
+
+	return output.Bytes(), nil
+}
+
+// htmlStatus is the status shown and filtered on in the report
+func htmlStatus(v shared.Violation) string {
+	if v.Skip {
+		return "skipped"
+	}
+	switch v.Severity() {
+	case shared.SeverityWarning:
+		return "warning"
+	case shared.SeverityInfo:
+		return "info"
+	default:
+		return "violation"
+	}
+}
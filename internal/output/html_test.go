@@ -0,0 +1,69 @@
+package output
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/jakebark/tag-nag/internal/shared"
+)
+
+func TestHTMLFormatter_Format(t *testing.T) {
+	testCases := []struct {
+		name        string
+		violations  []shared.Violation
+		resources   []shared.Resource
+		contains    []string
+		notContains []string
+	}{
+		{
+			name:      "no violations",
+			resources: []shared.Resource{{ResourceType: "aws_s3_bucket", ResourceName: "a", FilePath: "main.tf", Compliant: true}},
+			contains:  []string{"<title>tag-nag report</title>", "No tag violations found", "100.0%"},
+		},
+		{
+			name: "violations, coverage and drill-down",
+			violations: []shared.Violation{
+				{ResourceType: "aws_s3_bucket", ResourceName: "b", Findings: missingTags("Owner"), FilePath: "z.tf", Line: 4},
+				{ResourceType: "aws_instance", ResourceName: "<script>", Findings: missingTags("Owner"), FilePath: "main.tf", Line: 2, Skip: true, SkipReason: "legacy"},
+			},
+			resources: []shared.Resource{
+				{ResourceType: "aws_s3_bucket", ResourceName: "b", FilePath: "z.tf"},
+				{ResourceType: "aws_instance", ResourceName: "<script>", FilePath: "main.tf"},
+				{ResourceType: "aws_s3_bucket", ResourceName: "a", FilePath: "main.tf", Compliant: true},
+				{ResourceType: "aws_s3_bucket", ResourceName: "c", FilePath: "main.tf", Compliant: true},
+			},
+			contains: []string{
+				"50.0%", // compliance
+				`<div class="bar-label">Owner</div>`,
+				`<tr data-status="violation"><td>z.tf</td><td>4</td><td><code>aws_s3_bucket.b</code></td><td>Missing tags: Owner</td>`,
+				`skipped (ignored: &#34;legacy&#34;)`,
+				"aws_instance.&lt;script&gt;",
+				"<summary><code>main.tf</code> (1)</summary>",
+			},
+			notContains: []string{"aws_instance.<script>", "http://", "https://"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			formatter := &HTMLFormatter{RequiredTags: []string{"Owner"}}
+			output, err := formatter.Format(tc.violations, tc.resources)
+			if err != nil {
+				t.Fatalf("Format() error = %v", err)
+			}
+			for _, want := range tc.contains {
+				if !strings.Contains(string(output), want) {
+					t.Errorf("Output missing %q", want)
+				}
+			}
+			for _, unwanted := range tc.notContains {
+				if strings.Contains(string(output), unwanted) {
+					t.Errorf("Output should not contain %q", unwanted)
+				}
+			}
+			if strings.Index(string(output), "main.tf") > strings.Index(string(output), "z.tf") {
+				t.Error("Files should be sorted")
+			}
+		})
+	}
+}
@@ -7,7 +7,9 @@ import (
 )
 
 // JSONFormatter implements JSON output format
-type JSONFormatter struct{}
+type JSONFormatter struct {
+	RequiredTags []string // for coverage in the summary
+}
 
 // JSONOutput represents the structured JSON output format
 type JSONOutput struct {
@@ -17,32 +19,47 @@ type JSONOutput struct {
 
 // Summary provides aggregate information about violations
 type Summary struct {
-	Total         int `json:"total"`
-	Skipped       int `json:"skipped"`
-	FilesAffected int `json:"files_affected"`
+	Total         int       `json:"total"`
+	Skipped       int       `json:"skipped"`
+	Warnings      int       `json:"warnings"`
+	Info          int       `json:"info"`
+	FilesAffected int       `json:"files_affected"`
+	Coverage      *Coverage `json:"coverage,omitempty"`
 }
 
 // Format formats violations as JSON
-func (f *JSONFormatter) Format(violations []shared.Violation) ([]byte, error) {
-	var skipped int
+func (f *JSONFormatter) Format(violations []shared.Violation, resources []shared.Resource) ([]byte, error) {
+	coverage := measureCoverage(violations, resources, f.RequiredTags)
+	output := JSONOutput{
+		Violations: violations,
+		Summary:    summarize(violations),
+	}
+	output.Summary.Coverage = &coverage
+
+	return json.MarshalIndent(output, "", "  ")
+}
+
+// summarize counts violations by status and the files they are in
+func summarize(violations []shared.Violation) Summary {
+	var skipped, warnings, info int
 	files := make(map[string]bool)
 
 	for _, v := range violations {
 		if v.Skip {
 			skipped++
+		} else if v.Severity() == shared.SeverityWarning {
+			warnings++
+		} else if v.Severity() == shared.SeverityInfo {
+			info++
 		}
 		files[v.FilePath] = true
 	}
 
-	output := JSONOutput{
-		Violations: violations,
-		Summary: Summary{
-			Total:         len(violations),
-			Skipped:       skipped,
-			FilesAffected: len(files),
-		},
+	return Summary{
+		Total:         len(violations),
+		Skipped:       skipped,
+		Warnings:      warnings,
+		Info:          info,
+		FilesAffected: len(files),
 	}
-
-	return json.MarshalIndent(output, "", "  ")
 }
-
@@ -26,7 +26,7 @@ func TestJSONFormatter_Format(t *testing.T) {
 				{
 					ResourceType: "aws_s3_bucket",
 					ResourceName: "test",
-					MissingTags:  []string{"Owner"},
+					Findings:     missingTags("Owner"),
 					FilePath:     "main.tf",
 					Line:         10,
 				},
@@ -37,8 +37,8 @@ func TestJSONFormatter_Format(t *testing.T) {
 		{
 			name: "multiple violations",
 			violations: []shared.Violation{
-				{ResourceType: "aws_s3_bucket", ResourceName: "test1", MissingTags: []string{"Owner"}},
-				{ResourceType: "aws_instance", ResourceName: "test2", MissingTags: []string{"Env"}},
+				{ResourceType: "aws_s3_bucket", ResourceName: "test1", Findings: missingTags("Owner")},
+				{ResourceType: "aws_instance", ResourceName: "test2", Findings: missingTags("Env")},
 			},
 			wantJSON:   true,
 			wantFields: []string{"violations", "summary"},
@@ -56,7 +56,7 @@ func TestJSONFormatter_Format(t *testing.T) {
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
 			formatter := &JSONFormatter{}
-			output, err := formatter.Format(tc.violations)
+			output, err := formatter.Format(tc.violations, nil)
 
 			if err != nil {
 				t.Errorf("Format() error = %v", err)
@@ -77,4 +77,40 @@ func TestJSONFormatter_Format(t *testing.T) {
 			}
 		})
 	}
-}
\ No newline at end of file
+}
+
+func TestJSONFormatter_Coverage(t *testing.T) {
+	violations := []shared.Violation{
+		{ResourceType: "aws_s3_bucket", ResourceName: "a", Findings: missingTags("Owner"), FilePath: "main.tf", Line: 1},
+	}
+	resources := []shared.Resource{
+		{ResourceType: "aws_s3_bucket", ResourceName: "a", FilePath: "main.tf"},
+		{ResourceType: "aws_s3_bucket", ResourceName: "b", FilePath: "main.tf", Compliant: true},
+	}
+
+	output, err := (&JSONFormatter{RequiredTags: []string{"Owner"}}).Format(violations, resources)
+	if err != nil {
+		t.Fatalf("Format() error = %v", err)
+	}
+
+	var parsed JSONOutput
+	if err := json.Unmarshal(output, &parsed); err != nil {
+		t.Fatalf("Output is not valid JSON: %v", err)
+	}
+	coverage := parsed.Summary.Coverage
+	if coverage == nil {
+		t.Fatalf("Summary has no coverage:\n%s", output)
+	}
+	if coverage.Resources != 2 || coverage.Compliant != 1 || coverage.Percent != 50 {
+		t.Errorf("coverage = %d/%d (%.1f%%), want 1/2 (50.0%%)", coverage.Compliant, coverage.Resources, coverage.Percent)
+	}
+	if len(coverage.Tags) != 1 || coverage.Tags[0] != (TagCoverage{Tag: "Owner", Present: 1, Absent: 1}) {
+		t.Errorf("coverage.Tags = %+v, want Owner 1 present, 1 absent", coverage.Tags)
+	}
+	if _, ok := coverage.ByType["aws_s3_bucket"]; !ok {
+		t.Errorf("coverage.ByType = %+v, want aws_s3_bucket", coverage.ByType)
+	}
+	if _, ok := coverage.ByDirectory["."]; !ok {
+		t.Errorf("coverage.ByDirectory = %+v, want .", coverage.ByDirectory)
+	}
+}
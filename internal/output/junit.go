@@ -3,18 +3,34 @@ package output
 import (
 	"encoding/xml"
 	"fmt"
+	"sort"
 	"strings"
 
 	"github.com/jakebark/tag-nag/internal/shared"
 )
 
-type JUnitXMLFormatter struct{}
+type JUnitXMLFormatter struct {
+	PerFile bool            // a suite per file, with a test case for every resource checked
+	FailOn  shared.Severity // findings at or above it fail the test case, error when empty
+}
+
+type TestSuites struct {
+	XMLName    xml.Name    `xml:"testsuites"`
+	Name       string      `xml:"name,attr"`
+	Tests      int         `xml:"tests,attr"`
+	Failures   int         `xml:"failures,attr"`
+	Skipped    int         `xml:"skipped,attr"`
+	Time       string      `xml:"time,attr"`
+	TestSuites []TestSuite `xml:"testsuite"`
+}
 
 type TestSuite struct {
 	XMLName   xml.Name   `xml:"testsuite"`
 	Name      string     `xml:"name,attr"`
 	Tests     int        `xml:"tests,attr"`
 	Failures  int        `xml:"failures,attr"`
+	Skipped   int        `xml:"skipped,attr,omitempty"`
+	Time      string     `xml:"time,attr,omitempty"`
 	TestCases []TestCase `xml:"testcase"`
 }
 
@@ -22,30 +38,48 @@ type TestCase struct {
 	XMLName   xml.Name `xml:"testcase"`
 	Name      string   `xml:"name,attr"`
 	ClassName string   `xml:"classname,attr"`
+	Time      string   `xml:"time,attr,omitempty"`
 	Failure   *Failure `xml:"failure,omitempty"`
+	Skipped   *Skipped `xml:"skipped,omitempty"`
+	SystemOut string   `xml:"system-out,omitempty"`
+}
+
+type Skipped struct {
+	Message string `xml:"message,attr,omitempty"`
 }
 
 type Failure struct {
 	XMLName xml.Name `xml:"failure"`
 	Message string   `xml:"message,attr"`
+	Text    string   `xml:",chardata"`
 }
 
+// junitTime is the time attribute, tag-nag does not time individual resources
+const junitTime = "0"
+
 // Format formats violations as JUnit XML
-func (f *JUnitXMLFormatter) Format(violations []shared.Violation) ([]byte, error) {
+func (f *JUnitXMLFormatter) Format(violations []shared.Violation, resources []shared.Resource) ([]byte, error) {
+	if f.PerFile {
+		return f.formatPerFile(violations, resources)
+	}
+
 	var testCases []TestCase
 	failures := 0
 
 	for _, v := range violations {
 		testCase := TestCase{
-			Name:      fmt.Sprintf("%s.%s", v.ResourceType, v.ResourceName),
+			Name:      resourceAddress(v),
 			ClassName: v.FilePath,
 		}
 
-		if !v.Skip {
+		if !v.Skip && v.Severity().AtLeast(f.FailOn) {
 			failures++
 			testCase.Failure = &Failure{
-				Message: fmt.Sprintf("Missing tags: %s", strings.Join(v.MissingTags, ", ")),
+				Message: describeViolation(v),
+				Text:    describeFindings(v.Findings),
 			}
+		} else if !v.Skip {
+			testCase.SystemOut = describeFindings(v.Findings) // findings below --fail-on do not fail the test case
 		}
 
 		testCases = append(testCases, testCase)
@@ -66,3 +100,102 @@ func (f *JUnitXMLFormatter) Format(violations []shared.Violation) ([]byte, error
 	return []byte(xml.Header + string(output)), nil
 }
 
+// formatPerFile formats a suite per file, with a passing, failing or skipped test case for every resource
+func (f *JUnitXMLFormatter) formatPerFile(violations []shared.Violation, resources []shared.Resource) ([]byte, error) {
+	resourceViolations := make(map[string]shared.Violation)
+	for _, v := range violations {
+		resourceViolations[resourceKey(v.FilePath, v.ResourceType, v.ResourceName)] = v
+	}
+
+	type resourceCase struct {
+		line     int
+		testCase TestCase
+	}
+	fileCases := make(map[string][]resourceCase)
+	addCase := func(filePath string, line int, testCase TestCase) {
+		fileCases[filePath] = append(fileCases[filePath], resourceCase{line: line, testCase: testCase})
+	}
+
+	checked := make(map[string]bool)
+	for _, r := range resources {
+		key := resourceKey(r.FilePath, r.ResourceType, r.ResourceName)
+		checked[key] = true
+		if v, found := resourceViolations[key]; found {
+			addCase(v.FilePath, v.Line, junitTestCase(v, f.FailOn))
+		} else {
+			addCase(r.FilePath, r.Line, TestCase{Name: r.ResourceType + "." + r.ResourceName, ClassName: r.FilePath, Time: junitTime})
+		}
+	}
+	for _, v := range violations { // file-level violations, eg unattached ignore comments
+		if !checked[resourceKey(v.FilePath, v.ResourceType, v.ResourceName)] {
+			addCase(v.FilePath, v.Line, junitTestCase(v, f.FailOn))
+		}
+	}
+
+	filePaths := make([]string, 0, len(fileCases))
+	for filePath := range fileCases {
+		filePaths = append(filePaths, filePath)
+	}
+	sort.Strings(filePaths)
+
+	testSuites := TestSuites{Name: "tag-nag", Time: junitTime}
+	for _, filePath := range filePaths {
+		cases := fileCases[filePath]
+		sort.SliceStable(cases, func(i, j int) bool { return cases[i].line < cases[j].line })
+
+		testSuite := TestSuite{Name: filePath, Time: junitTime}
+		for _, c := range cases {
+			testSuite.TestCases = append(testSuite.TestCases, c.testCase)
+			if c.testCase.Failure != nil {
+				testSuite.Failures++
+			}
+			if c.testCase.Skipped != nil {
+				testSuite.Skipped++
+			}
+		}
+		testSuite.Tests = len(testSuite.TestCases)
+
+		testSuites.Tests += testSuite.Tests
+		testSuites.Failures += testSuite.Failures
+		testSuites.Skipped += testSuite.Skipped
+		testSuites.TestSuites = append(testSuites.TestSuites, testSuite)
+	}
+
+	output, err := xml.MarshalIndent(testSuites, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	return []byte(xml.Header + string(output)), nil
+}
+
+// junitTestCase is the test case for a resource with a violation, failed at or above failOn and skipped when ignored
+func junitTestCase(v shared.Violation, failOn shared.Severity) TestCase {
+	testCase := TestCase{
+		Name:      resourceAddress(v),
+		ClassName: v.FilePath,
+		Time:      junitTime,
+	}
+
+	switch {
+	case v.Skip:
+		testCase.Skipped = &Skipped{Message: describeIgnored(v.SkipReason)}
+	case v.Severity().AtLeast(failOn):
+		testCase.Failure = &Failure{
+			Message: describeViolation(v),
+			Text:    describeFindings(v.Findings),
+		}
+	default:
+		testCase.SystemOut = describeFindings(v.Findings) // findings below --fail-on do not fail the test case
+	}
+	return testCase
+}
+
+// describeFindings lists one finding per line with its rule ID, eg "missing-tag: Owner"
+func describeFindings(findings []shared.Finding) string {
+	var lines []string
+	for _, f := range findings {
+		lines = append(lines, fmt.Sprintf("%s: %s", f.RuleID, describeFinding(f)))
+	}
+	return strings.Join(lines, "\n")
+}
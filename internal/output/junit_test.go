@@ -12,6 +12,7 @@ func TestJUnitXMLFormatter_Format(t *testing.T) {
 	testCases := []struct {
 		name         string
 		violations   []shared.Violation
+		failOn       shared.Severity
 		wantXML      bool
 		wantTests    int
 		wantFailures int
@@ -29,7 +30,7 @@ func TestJUnitXMLFormatter_Format(t *testing.T) {
 				{
 					ResourceType: "aws_s3_bucket",
 					ResourceName: "test",
-					MissingTags:  []string{"Owner"},
+					Findings:     missingTags("Owner"),
 					FilePath:     "main.tf",
 					Line:         10,
 				},
@@ -41,8 +42,8 @@ func TestJUnitXMLFormatter_Format(t *testing.T) {
 		{
 			name: "multiple violations",
 			violations: []shared.Violation{
-				{ResourceType: "aws_s3_bucket", ResourceName: "test1", MissingTags: []string{"Owner"}},
-				{ResourceType: "aws_instance", ResourceName: "test2", MissingTags: []string{"Env"}},
+				{ResourceType: "aws_s3_bucket", ResourceName: "test1", Findings: missingTags("Owner")},
+				{ResourceType: "aws_instance", ResourceName: "test2", Findings: missingTags("Env")},
 			},
 			wantXML:      true,
 			wantTests:    2,
@@ -60,19 +61,40 @@ func TestJUnitXMLFormatter_Format(t *testing.T) {
 		{
 			name: "mixed violations",
 			violations: []shared.Violation{
-				{ResourceType: "aws_s3_bucket", ResourceName: "test1", MissingTags: []string{"Owner"}},
+				{ResourceType: "aws_s3_bucket", ResourceName: "test1", Findings: missingTags("Owner")},
 				{ResourceType: "aws_instance", ResourceName: "test2", Skip: true},
 			},
 			wantXML:      true,
 			wantTests:    2,
 			wantFailures: 1,
 		},
+		{
+			name: "warning violation",
+			violations: []shared.Violation{
+				{ResourceType: "aws_s3_bucket", ResourceName: "test1", Findings: missingTags("Owner")},
+				{ResourceType: "aws_instance", ResourceName: "test2", Findings: warningTags("CostCenter")},
+			},
+			wantXML:      true,
+			wantTests:    2,
+			wantFailures: 1,
+		},
+		{
+			name: "warning violation with fail on warning",
+			violations: []shared.Violation{
+				{ResourceType: "aws_s3_bucket", ResourceName: "test1", Findings: missingTags("Owner")},
+				{ResourceType: "aws_instance", ResourceName: "test2", Findings: warningTags("CostCenter")},
+			},
+			failOn:       shared.SeverityWarning,
+			wantXML:      true,
+			wantTests:    2,
+			wantFailures: 2,
+		},
 	}
 
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
-			formatter := &JUnitXMLFormatter{}
-			output, err := formatter.Format(tc.violations)
+			formatter := &JUnitXMLFormatter{FailOn: tc.failOn}
+			output, err := formatter.Format(tc.violations, nil)
 
 			if err != nil {
 				t.Errorf("Format() error = %v", err)
@@ -99,4 +121,86 @@ func TestJUnitXMLFormatter_Format(t *testing.T) {
 			}
 		})
 	}
-}
\ No newline at end of file
+}
+
+func TestJUnitXMLFormatter_FormatPerFile(t *testing.T) {
+	violations := []shared.Violation{
+		{ResourceType: "aws_s3_bucket", ResourceName: "b", Findings: missingTags("Owner"), FilePath: "z.tf", Line: 4},
+		{ResourceType: "aws_instance", ResourceName: "old", Findings: suppressedTags("Owner"), FilePath: "main.tf", Line: 2, Skip: true, SkipReason: "legacy"},
+		{ResourceType: "aws_instance", ResourceName: "web", Findings: warningTags("CostCenter"), FilePath: "main.tf", Line: 20},
+		{Findings: []shared.Finding{{RuleID: shared.RuleUnattachedIgnore, Severity: shared.SeverityError}}, FilePath: "main.tf", Line: 30},
+	}
+	resources := []shared.Resource{
+		{ResourceType: "aws_s3_bucket", ResourceName: "b", FilePath: "z.tf", Line: 4},
+		{ResourceType: "aws_instance", ResourceName: "old", FilePath: "main.tf", Line: 2},
+		{ResourceType: "aws_s3_bucket", ResourceName: "a", FilePath: "main.tf", Line: 10, Compliant: true},
+		{ResourceType: "aws_instance", ResourceName: "web", FilePath: "main.tf", Line: 20},
+	}
+
+	output, err := (&JUnitXMLFormatter{PerFile: true}).Format(violations, resources)
+	if err != nil {
+		t.Fatalf("Format() error = %v", err)
+	}
+
+	var testSuites TestSuites
+	if err := xml.Unmarshal(output, &testSuites); err != nil {
+		t.Fatalf("Output is not valid XML: %v", err)
+	}
+	if testSuites.Tests != 5 || testSuites.Failures != 2 || testSuites.Skipped != 1 || testSuites.Time == "" {
+		t.Errorf("Testsuites = tests %d, failures %d, skipped %d, time %q; want 5, 2, 1 and a time",
+			testSuites.Tests, testSuites.Failures, testSuites.Skipped, testSuites.Time)
+	}
+	if len(testSuites.TestSuites) != 2 {
+		t.Fatalf("Suites count = %d; want 2", len(testSuites.TestSuites))
+	}
+
+	mainSuite := testSuites.TestSuites[0]
+	if mainSuite.Name != "main.tf" || mainSuite.Tests != 4 || mainSuite.Failures != 1 || mainSuite.Skipped != 1 {
+		t.Errorf("First suite = %s, tests %d, failures %d, skipped %d; want main.tf, 4, 1, 1",
+			mainSuite.Name, mainSuite.Tests, mainSuite.Failures, mainSuite.Skipped)
+	}
+
+	var names []string
+	for _, testCase := range mainSuite.TestCases {
+		names = append(names, testCase.Name)
+	}
+	if got := strings.Join(names, ","); got != "aws_instance.old,aws_s3_bucket.a,aws_instance.web,file" {
+		t.Errorf("Test cases = %s; want sorted by line", got)
+	}
+
+	skipped := mainSuite.TestCases[0]
+	if skipped.Skipped == nil || skipped.Skipped.Message != `(ignored: "legacy")` || skipped.Failure != nil {
+		t.Errorf("Ignored resource = %+v; want skipped with the reason", skipped)
+	}
+	if passed := mainSuite.TestCases[1]; passed.Failure != nil || passed.Skipped != nil || passed.Time == "" {
+		t.Errorf("Compliant resource = %+v; want a passing test case with a time", passed)
+	}
+	if warning := mainSuite.TestCases[2]; warning.Failure != nil || warning.SystemOut == "" {
+		t.Errorf("Warning resource = %+v; want a passing test case with system-out", warning)
+	}
+}
+
+func TestJUnitXMLFormatter_FormatPerFileFailOn(t *testing.T) {
+	violations := []shared.Violation{
+		{ResourceType: "aws_instance", ResourceName: "web", Findings: warningTags("CostCenter"), FilePath: "main.tf", Line: 20},
+	}
+	resources := []shared.Resource{
+		{ResourceType: "aws_instance", ResourceName: "web", FilePath: "main.tf", Line: 20},
+	}
+
+	output, err := (&JUnitXMLFormatter{PerFile: true, FailOn: shared.SeverityWarning}).Format(violations, resources)
+	if err != nil {
+		t.Fatalf("Format() error = %v", err)
+	}
+
+	var testSuites TestSuites
+	if err := xml.Unmarshal(output, &testSuites); err != nil {
+		t.Fatalf("Output is not valid XML: %v", err)
+	}
+	if testSuites.Failures != 1 || len(testSuites.TestSuites) != 1 {
+		t.Fatalf("Testsuites = failures %d, suites %d; want 1 and 1", testSuites.Failures, len(testSuites.TestSuites))
+	}
+	if warning := testSuites.TestSuites[0].TestCases[0]; warning.Failure == nil || warning.SystemOut != "" {
+		t.Errorf("Warning resource = %+v; want a failing test case at --fail-on warning", warning)
+	}
+}
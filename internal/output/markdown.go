@@ -0,0 +1,97 @@
+package output
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/jakebark/tag-nag/internal/shared"
+)
+
+// MarkdownFormatter implements markdown output, for pull request comments
+type MarkdownFormatter struct {
+	MaxSize int // bytes, 0 is unlimited
+}
+
+// Format formats violations as a markdown report, truncated to MaxSize
+func (f *MarkdownFormatter) Format(violations []shared.Violation, resources []shared.Resource) ([]byte, error) {
+	var output strings.Builder
+
+	output.WriteString("## tag-nag report\n\n")
+	if len(violations) == 0 {
+		output.WriteString("✅ No tag violations found\n")
+		return []byte(output.String()), nil
+	}
+
+	summary := summarize(violations)
+	output.WriteString("| Total | Skipped | Files affected |\n")
+	output.WriteString("|---|---|---|\n")
+	output.WriteString(fmt.Sprintf("| %d | %d | %d |\n\n", summary.Total, summary.Skipped, summary.FilesAffected))
+
+	fileGroups := make(map[string][]shared.Violation)
+	for _, v := range violations {
+		fileGroups[v.FilePath] = append(fileGroups[v.FilePath], v)
+	}
+	filePaths := make([]string, 0, len(fileGroups))
+	for filePath := range fileGroups {
+		filePaths = append(filePaths, filePath)
+	}
+	sort.Strings(filePaths)
+
+	const sectionEnd = "\n</details>\n\n"
+	const truncationNote = "> ⚠️ Report truncated to %d bytes, %d violation(s) not shown. Use `-o json` for the full results.\n"
+	budget := f.MaxSize - len(truncationNote) - 20 // room for the counts
+
+	shown := 0
+files:
+	for _, filePath := range filePaths {
+		fileViolations := fileGroups[filePath]
+		sort.SliceStable(fileViolations, func(i, j int) bool {
+			return fileViolations[i].Line < fileViolations[j].Line
+		})
+
+		link := relativePath(filePath)
+		section := fmt.Sprintf("<details>\n<summary><code>%s</code> (%d)</summary>\n\n", link, len(fileViolations)) +
+			"| Line | Resource | Issues |\n" +
+			"|---|---|---|\n"
+		if f.exceeds(output.Len()+len(section)+len(sectionEnd), budget) {
+			break
+		}
+		output.WriteString(section)
+
+		for _, v := range fileViolations {
+			issues := describeViolation(v)
+			if v.Skip {
+				issues = "skipped"
+				if v.SkipReason != "" {
+					issues += " " + describeIgnored(v.SkipReason)
+				}
+			}
+			row := fmt.Sprintf("| [%d](%s#L%d) | `%s` | %s |\n", v.Line, link, v.Line, resourceAddress(v), escapeMarkdownCell(issues))
+			if f.exceeds(output.Len()+len(row)+len(sectionEnd), budget) {
+				output.WriteString(sectionEnd)
+				break files
+			}
+			output.WriteString(row)
+			shown++
+		}
+		output.WriteString(sectionEnd)
+	}
+
+	if shown < len(violations) {
+		output.WriteString(fmt.Sprintf(truncationNote, f.MaxSize, len(violations)-shown))
+	}
+
+	return []byte(output.String()), nil
+}
+
+// exceeds reports whether a report size is over budget, when a maximum size is set
+func (f *MarkdownFormatter) exceeds(size int, budget int) bool {
+	return f.MaxSize > 0 && size > budget
+}
+
+// escapeMarkdownCell stops pipes and newlines from breaking a table row
+func escapeMarkdownCell(text string) string {
+	text = strings.ReplaceAll(text, "|", `\|`)
+	return strings.ReplaceAll(text, "\n", " ")
+}
@@ -0,0 +1,92 @@
+package output
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/jakebark/tag-nag/internal/shared"
+)
+
+func TestMarkdownFormatter_Format(t *testing.T) {
+	testCases := []struct {
+		name            string
+		violations      []shared.Violation
+		maxSize         int
+		wantContains    []string
+		wantNotContains []string
+	}{
+		{
+			name:         "empty violations",
+			violations:   []shared.Violation{},
+			wantContains: []string{"No tag violations found"},
+		},
+		{
+			name: "summary and file sections",
+			violations: []shared.Violation{
+				{ResourceType: "aws_s3_bucket", ResourceName: "test", Findings: missingTags("Owner"), FilePath: "main.tf", Line: 10},
+				{ResourceType: "aws_instance", ResourceName: "web", FilePath: "modules/ec2.tf", Line: 3, Skip: true, SkipReason: "legacy"},
+			},
+			wantContains: []string{
+				"| 2 | 1 | 2 |",
+				"<summary><code>main.tf</code> (1)</summary>",
+				"| [10](main.tf#L10) | `aws_s3_bucket.test` | Missing tags: Owner |",
+				"| [3](modules/ec2.tf#L3) | `aws_instance.web` | skipped (ignored: \"legacy\") |",
+			},
+			wantNotContains: []string{"truncated"},
+		},
+		{
+			name: "pipes are escaped",
+			violations: []shared.Violation{
+				{ResourceType: "aws_s3_bucket", ResourceName: "test", Findings: []shared.Finding{{RuleID: shared.RuleInvalidTagValue, Tag: "Env", Expected: []string{"a|b"}, Actual: "c", Severity: shared.SeverityError}}, FilePath: "main.tf", Line: 1},
+			},
+			wantContains: []string{`Env[a\|b]`},
+		},
+		{
+			name:         "truncated",
+			violations:   manyViolations(200),
+			maxSize:      2000,
+			wantContains: []string{"Report truncated to 2000 bytes", "not shown", "</details>"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			formatter := &MarkdownFormatter{MaxSize: tc.maxSize}
+			output, err := formatter.Format(tc.violations, nil)
+			if err != nil {
+				t.Fatalf("Format() error = %v", err)
+			}
+
+			outputStr := string(output)
+			if tc.maxSize > 0 && len(outputStr) > tc.maxSize {
+				t.Errorf("Output is %d bytes, want at most %d", len(outputStr), tc.maxSize)
+			}
+			for _, want := range tc.wantContains {
+				if !strings.Contains(outputStr, want) {
+					t.Errorf("Output missing %q", want)
+				}
+			}
+			for _, notWant := range tc.wantNotContains {
+				if strings.Contains(outputStr, notWant) {
+					t.Errorf("Output should not contain %q", notWant)
+				}
+			}
+		})
+	}
+}
+
+// manyViolations builds violations spread across files
+func manyViolations(count int) []shared.Violation {
+	violations := make([]shared.Violation, count)
+	for i := range violations {
+		violations[i] = shared.Violation{
+			ResourceType: "aws_s3_bucket",
+			ResourceName: fmt.Sprintf("bucket_%d", i),
+			Findings:     missingTags("Owner", "Environment"),
+			FilePath:     fmt.Sprintf("file_%d.tf", i%10),
+			Line:         i + 1,
+		}
+	}
+	return violations
+}
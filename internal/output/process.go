@@ -5,44 +5,123 @@ import (
 	"log"
 	"os"
 
+	"github.com/jakebark/tag-nag/internal/config"
 	"github.com/jakebark/tag-nag/internal/shared"
 )
 
-// ProcessOutput handles the output formatting and exit logic
-func ProcessOutput(violations []shared.Violation, format shared.OutputFormat, dryRun bool, outputFile string) {
-	formatter := GetFormatter(format)
-	formattedOutput, err := formatter.Format(violations)
-	if err != nil {
-		log.Fatalf("Error formatting output: %v", err)
+// ProcessOutput writes each output and handles the exit logic
+// parse errors are reported alongside the violations. They are warnings that fail the run at --fail-on warning,
+// or errors with --strict, which exit with ExitParseError
+func ProcessOutput(violations []shared.Violation, resources []shared.Resource, parseErrors []shared.ParseError, outputs []shared.Output, dryRun bool, failOn shared.Severity, thresholds shared.Thresholds, options shared.OutputOptions) {
+	nonSkippedCount := 0
+	belowThresholdCount := 0 // eg warnings, when failing on errors
+	for _, v := range violations {
+		if v.Skip {
+			continue
+		}
+		if v.Severity().AtLeast(failOn) {
+			nonSkippedCount++
+		} else {
+			belowThresholdCount++
+		}
 	}
+	failures := thresholdFailures(nonSkippedCount, measureCoverage(violations, resources, options.RequiredTags), thresholds)
 
-	if len(formattedOutput) > 0 {
-		if outputFile != "" {
-			if err := os.WriteFile(outputFile, formattedOutput, 0644); err != nil {
-				log.Fatalf("Error writing output file: %v", err)
-			}
-			log.Printf("Output written to %s", outputFile)
-		} else {
-			fmt.Print(string(formattedOutput))
+	unparsedFiles := make(map[string]bool)
+	failingParse := false // a file could not be parsed, at or above the fail-on severity
+	strictParse := false  // a file could not be parsed, with --strict
+	for _, parseError := range parseErrors {
+		unparsedFiles[parseError.FilePath] = true
+		failingParse = failingParse || parseError.Severity.AtLeast(failOn)
+		strictParse = strictParse || parseError.Severity == shared.SeverityError
+		violations = append(violations, parseError.Violation())
+	}
+	violations = sortViolations(violations, options.SortBy)
+	resources = sortResources(resources)
+
+	for _, output := range outputs {
+		writeOutput(violations, resources, output, options)
+	}
+
+	if belowThresholdCount > 0 {
+		log.Printf("\033[33mFound %d tag violation(s) below the --fail-on %s threshold\033[0m\n", belowThresholdCount, failOn)
+	}
+
+	if nonSkippedCount > 0 {
+		colour := "\033[31m"
+		if dryRun {
+			colour = "\033[32m"
+		} else if len(failures) == 0 {
+			colour = "\033[33m" // within --max-violations
 		}
+		log.Printf("%sFound %d tag violation(s)\033[0m\n", colour, nonSkippedCount)
+	} else if belowThresholdCount == 0 {
+		log.Println("No tag violations found")
 	}
 
-	nonSkippedCount := 0
-	for _, v := range violations {
-		if !v.Skip {
-			nonSkippedCount++
+	// the default fails on any violation, which needs no explanation
+	if thresholds != (shared.Thresholds{}) {
+		for _, failure := range failures {
+			log.Printf("\033[31m%s\033[0m\n", failure)
+		}
+	}
+	if len(unparsedFiles) > 0 {
+		colour := "\033[33m"
+		if failingParse && !dryRun {
+			colour = "\033[31m"
 		}
+		log.Printf("%sCould not parse %d file(s)\033[0m\n", colour, len(unparsedFiles))
 	}
 
-	if nonSkippedCount > 0 && dryRun {
-		log.Printf("\033[32mFound %d tag violation(s)\033[0m\n", nonSkippedCount)
-		os.Exit(0)
-	} else if nonSkippedCount > 0 {
-		log.Printf("\033[31mFound %d tag violation(s)\033[0m\n", nonSkippedCount)
-		os.Exit(1)
-	} else {
-		log.Println("No tag violations found")
+	switch {
+	case dryRun:
 		os.Exit(0)
+	case strictParse:
+		os.Exit(config.ExitParseError)
+	case failingParse, len(failures) > 0:
+		os.Exit(config.ExitViolations)
+	}
+	os.Exit(0)
+}
+
+// thresholdFailures describes each threshold the run breaks, empty when it passes
+func thresholdFailures(violationCount int, coverage Coverage, thresholds shared.Thresholds) []string {
+	var failures []string
+	if thresholds.MaxViolations >= 0 && violationCount > thresholds.MaxViolations {
+		failures = append(failures, fmt.Sprintf("%d tag violation(s) is more than the --max-violations %d", violationCount, thresholds.MaxViolations))
+	}
+	if coverage.Percent < thresholds.MinCompliance {
+		failures = append(failures, fmt.Sprintf("%.1f%% of resources are compliant, below the --min-compliance %g%%", coverage.Percent, thresholds.MinCompliance))
 	}
+	return failures
 }
 
+// writeOutput formats violations and writes them to the output file, or stdout
+func writeOutput(violations []shared.Violation, resources []shared.Resource, output shared.Output, options shared.OutputOptions) {
+	formatter := GetFormatter(output.Format, options)
+	formattedOutput, err := formatter.Format(violations, resources)
+	if err != nil {
+		log.Printf("Error formatting %s output: %v", output.Format, err)
+		os.Exit(config.ExitUsage)
+	}
+
+	if len(formattedOutput) == 0 {
+		return
+	}
+	if output.File != "" {
+		if err := os.WriteFile(output.File, formattedOutput, 0644); err != nil {
+			log.Printf("Error writing output file: %v", err)
+			os.Exit(config.ExitUsage)
+		}
+		log.Printf("Output written to %s", output.File)
+	} else {
+		fmt.Print(string(formattedOutput))
+	}
+
+	if output.Format == shared.OutputFormatGitHub && options.GitHubSummaryFile != "" {
+		if err := writeJobSummary(options.GitHubSummaryFile, violations, resources); err != nil {
+			log.Printf("Error writing job summary: %v", err)
+			os.Exit(config.ExitUsage)
+		}
+	}
+}
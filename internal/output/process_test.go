@@ -0,0 +1,63 @@
+package output
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/jakebark/tag-nag/internal/shared"
+)
+
+func TestThresholdFailures(t *testing.T) {
+	testCases := []struct {
+		name           string
+		violationCount int
+		percent        float64
+		thresholds     shared.Thresholds
+		expected       []string
+	}{
+		{
+			name:     "no violations",
+			percent:  100,
+			expected: nil,
+		},
+		{
+			name:           "any violation fails by default",
+			violationCount: 1,
+			percent:        50,
+			expected:       []string{"1 tag violation(s) is more than the --max-violations 0"},
+		},
+		{
+			name:           "within max violations",
+			violationCount: 3,
+			percent:        50,
+			thresholds:     shared.Thresholds{MaxViolations: 3},
+			expected:       nil,
+		},
+		{
+			name:           "min compliance met",
+			violationCount: 3,
+			percent:        96,
+			thresholds:     shared.Thresholds{MaxViolations: -1, MinCompliance: 95},
+			expected:       nil,
+		},
+		{
+			name:           "both broken",
+			violationCount: 4,
+			percent:        80,
+			thresholds:     shared.Thresholds{MaxViolations: 3, MinCompliance: 95},
+			expected: []string{
+				"4 tag violation(s) is more than the --max-violations 3",
+				"80.0% of resources are compliant, below the --min-compliance 95%",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := thresholdFailures(tc.violationCount, Coverage{Percent: tc.percent}, tc.thresholds)
+			if diff := cmp.Diff(tc.expected, got); diff != "" {
+				t.Errorf("thresholdFailures() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -8,7 +8,14 @@ import (
 	"github.com/jakebark/tag-nag/internal/shared"
 )
 
-type SARIFFormatter struct{}
+// sarifLegacyRuleID is the single rule every result was reported under, before there was a rule per finding
+const sarifLegacyRuleID = "missing-tags"
+
+// SARIFFormatter implements SARIF v2.1.0, for GitHub code scanning and similar
+type SARIFFormatter struct {
+	RequiredTags []string                   // each gets a rule, whether or not it was broken
+	Severities   map[string]shared.Severity // by required tag key, for the rules' default levels
+}
 
 type sarifOutput struct {
 	Schema  string     `json:"$schema"`
@@ -19,61 +26,140 @@ type sarifOutput struct {
 type sarifRun struct {
 	Tool struct {
 		Driver struct {
-			Name           string `json:"name"`
-			InformationURI string `json:"informationUri"`
+			Name           string      `json:"name"`
+			InformationURI string      `json:"informationUri"`
+			Rules          []sarifRule `json:"rules"`
 		} `json:"driver"`
 	} `json:"tool"`
 	Results []sarifResult `json:"results"`
 }
 
+type sarifRule struct {
+	ID                   string       `json:"id"`
+	DeprecatedIDs        []string     `json:"deprecatedIds,omitempty"` // rule IDs of earlier versions, eg missing-tags
+	Name                 string       `json:"name"`
+	ShortDescription     sarifMessage `json:"shortDescription"`
+	FullDescription      sarifMessage `json:"fullDescription"`
+	Help                 sarifHelp    `json:"help"`
+	DefaultConfiguration struct {
+		Level string `json:"level"`
+	} `json:"defaultConfiguration"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifHelp struct {
+	Text     string `json:"text"`
+	Markdown string `json:"markdown"`
+}
+
 type sarifResult struct {
-	RuleID  string `json:"ruleId"`
-	Kind    string `json:"kind"`
-	Level   string `json:"level,omitempty"`
-	Message struct {
-		Text string `json:"text"`
-	} `json:"message"`
-	Locations []sarifLocation `json:"locations"`
+	RuleID              string             `json:"ruleId"`
+	RuleIndex           int                `json:"ruleIndex"`
+	Kind                string             `json:"kind"`
+	Level               string             `json:"level,omitempty"`
+	Message             sarifMessage       `json:"message"`
+	Locations           []sarifLocation    `json:"locations"`
+	PartialFingerprints map[string]string  `json:"partialFingerprints"`
+	Suppressions        []sarifSuppression `json:"suppressions,omitempty"`
+	Fixes               []sarifFix         `json:"fixes,omitempty"`
+	Properties          struct {
+		Finding shared.Finding `json:"finding"`
+	} `json:"properties"`
 }
 
 type sarifLocation struct {
 	PhysicalLocation struct {
-		ArtifactLocation struct {
-			URI string `json:"uri"`
-		} `json:"artifactLocation"`
-		Region struct {
-			StartLine int `json:"startLine"`
-		} `json:"region"`
+		ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+		Region           sarifRegion           `json:"region"`
 	} `json:"physicalLocation"`
 }
 
-// Format formats violations as SARIF v2.1.0
-func (f *SARIFFormatter) Format(violations []shared.Violation) ([]byte, error) {
-	var results []sarifResult
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+type sarifRegion struct {
+	StartLine   int `json:"startLine"`
+	StartColumn int `json:"startColumn,omitempty"`
+	EndLine     int `json:"endLine,omitempty"`
+	EndColumn   int `json:"endColumn,omitempty"`
+}
+
+type sarifSuppression struct {
+	Kind          string `json:"kind"`
+	Justification string `json:"justification,omitempty"`
+}
+
+type sarifFix struct {
+	Description     sarifMessage          `json:"description"`
+	ArtifactChanges []sarifArtifactChange `json:"artifactChanges"`
+}
+
+type sarifArtifactChange struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Replacements     []sarifReplacement    `json:"replacements"`
+}
+
+type sarifReplacement struct {
+	DeletedRegion   sarifRegion `json:"deletedRegion"`
+	InsertedContent struct {
+		Text string `json:"text"`
+	} `json:"insertedContent"`
+}
+
+// Format formats violations as SARIF v2.1.0, one result per finding
+func (f *SARIFFormatter) Format(violations []shared.Violation, resources []shared.Resource) ([]byte, error) {
+	rules := newSARIFRules(f.RequiredTags, f.Severities)
+	results := []sarifResult{}
 
 	for _, v := range violations {
-		r := sarifResult{RuleID: "missing-tags"}
-		r.Message.Text = fmt.Sprintf("%s %q is missing tags: %s",
-			v.ResourceType, v.ResourceName, strings.Join(v.MissingTags, ", "))
-
-		if v.Skip {
-			r.Kind = "notApplicable"
-		} else {
-			r.Kind = "fail"
-			r.Level = "error"
-		}
+		for _, finding := range v.Findings {
+			ruleID := sarifRuleID(finding)
+			r := sarifResult{
+				RuleID:    ruleID,
+				RuleIndex: rules.index(ruleID, finding),
+				Message:   sarifMessage{Text: fmt.Sprintf("%s: %s", describeResource(v), describeFinding(finding))},
+				PartialFingerprints: map[string]string{
+					"tagNagFinding/v1": findingFingerprint(v, finding),
+				},
+			}
+			r.Properties.Finding = finding
+
+			loc := sarifLocation{}
+			loc.PhysicalLocation.ArtifactLocation.URI = relativePath(v.FilePath)
+			loc.PhysicalLocation.Region = sarifRegion{StartLine: v.Line, EndLine: v.EndLine}
+			if finding.Line > 0 {
+				loc.PhysicalLocation.Region = sarifRegion{StartLine: finding.Line}
+			}
+			r.Locations = []sarifLocation{loc}
 
-		loc := sarifLocation{}
-		loc.PhysicalLocation.ArtifactLocation.URI = v.FilePath
-		loc.PhysicalLocation.Region.StartLine = v.Line
-		r.Locations = []sarifLocation{loc}
+			if v.Skip || finding.Suppressed {
+				reason := finding.Reason
+				if reason == "" {
+					reason = v.SkipReason
+				}
+				r.Kind = "notApplicable"
+				r.Suppressions = []sarifSuppression{{Kind: "inSource", Justification: reason}}
+			} else {
+				r.Kind = "fail"
+				r.Level = sarifLevel(finding.Severity)
+			}
 
-		results = append(results, r)
+			if fix, ok := sarifTagFix(v, finding); ok {
+				r.Fixes = []sarifFix{fix}
+			}
+
+			results = append(results, r)
+		}
 	}
 
 	run := sarifRun{Results: results}
 	run.Tool.Driver.Name = "tag-nag"
 	run.Tool.Driver.InformationURI = "https://github.com/jakebark/tag-nag"
+	run.Tool.Driver.Rules = rules.rules
 
 	output := sarifOutput{
 		Schema:  "https://raw.githubusercontent.com/oasis-tcs/sarif-spec/main/sarif-2.1/schema/sarif-schema-2.1.0.json",
@@ -83,3 +169,149 @@ func (f *SARIFFormatter) Format(violations []shared.Violation) ([]byte, error) {
 
 	return json.MarshalIndent(output, "", "  ")
 }
+
+// sarifRuleID is the rule a finding is reported under, per tag for tag rules, eg "missing-tag/Owner"
+func sarifRuleID(finding shared.Finding) string {
+	switch finding.RuleID {
+	case shared.RuleMissingTag, shared.RuleInvalidTagValue:
+		return finding.RuleID + "/" + finding.Tag
+	default:
+		return finding.RuleID
+	}
+}
+
+// sarifRules is the driver rule list, in the order rules are first used
+type sarifRules struct {
+	rules   []sarifRule
+	indexes map[string]int
+}
+
+// newSARIFRules starts the rule list with a missing-tag rule for every required tag, at the tag's severity
+func newSARIFRules(requiredTags []string, severities map[string]shared.Severity) *sarifRules {
+	rules := &sarifRules{indexes: make(map[string]int)}
+	for _, tag := range requiredTags {
+		finding := shared.Finding{RuleID: shared.RuleMissingTag, Tag: tag, Severity: severities[tag]}
+		rules.index(sarifRuleID(finding), finding)
+	}
+	return rules
+}
+
+// index returns the position of a rule, adding it on first use
+func (r *sarifRules) index(ruleID string, finding shared.Finding) int {
+	if i, ok := r.indexes[ruleID]; ok {
+		return i
+	}
+	r.indexes[ruleID] = len(r.rules)
+	r.rules = append(r.rules, newSARIFRule(ruleID, finding))
+	return r.indexes[ruleID]
+}
+
+// newSARIFRule describes a rule, using the finding that first broke it
+// the rule's default level is the finding's severity, as configured for the tag or rule
+func newSARIFRule(ruleID string, finding shared.Finding) sarifRule {
+	var short, full, help, helpMarkdown string
+	switch finding.RuleID {
+	case shared.RuleMissingTag:
+		short = fmt.Sprintf("Resource is missing the %s tag", finding.Tag)
+		full = fmt.Sprintf("Every taggable resource must have a %s tag, on the resource or inherited from provider default_tags.", finding.Tag)
+		help = fmt.Sprintf("Add the %s tag to the resource, or to the provider's default_tags.", finding.Tag)
+		helpMarkdown = fmt.Sprintf("Add the `%s` tag to the resource, or to the provider's `default_tags`. To accept a resource without it, add a `tag-nag ignore %s` comment, see [skip checks](https://github.com/jakebark/tag-nag#skip-checks).", finding.Tag, finding.Tag)
+	case shared.RuleInvalidTagValue:
+		short = fmt.Sprintf("%s tag has a disallowed value", finding.Tag)
+		full = fmt.Sprintf("The %s tag must have one of the allowed values.", finding.Tag)
+		if len(finding.Expected) > 0 {
+			full = fmt.Sprintf("The %s tag must be one of: %s.", finding.Tag, strings.Join(finding.Expected, ", "))
+		}
+		help = fmt.Sprintf("Change the %s tag to an allowed value, where it is set on the resource or in default_tags.", finding.Tag)
+		helpMarkdown = fmt.Sprintf("Change the `%s` tag to an allowed value, where it is set on the resource or in `default_tags`.", finding.Tag)
+	case shared.RuleDuplicateTagKey:
+		short = "Tag keys differ only by case"
+		full = "AWS treats tag keys that differ only by case as distinct tags, eg Owner and owner."
+		help = "Remove or rename one of the keys, so only one casing is used."
+		helpMarkdown = help
+	case shared.RuleTagKeyStyle:
+		short = "Tag key does not match the required style"
+		full = "Tag keys must follow the configured case style and prefixes."
+		help = "Rename the key to match --key-style and --key-prefixes."
+		helpMarkdown = "Rename the key to match `--key-style` and `--key-prefixes`."
+	case shared.RuleIgnoreWithoutReason:
+		short = "Ignore comment has no reason"
+		full = `Ignore comments must give a reason="...", so they are not honoured.`
+		help = `Add reason="..." to the ignore comment, saying why the resource is exempt.`
+		helpMarkdown = "Add `reason=\"...\"` to the ignore comment, saying why the resource is exempt."
+	case shared.RuleExpiredIgnore:
+		short = "Ignore comment has expired"
+		full = "The until= date on an ignore comment has passed, so it is no longer honoured."
+		help = "Fix the findings and remove the ignore comment, or move its until= date."
+		helpMarkdown = "Fix the findings and remove the ignore comment, or move its `until=` date."
+	case shared.RuleUnusedIgnore:
+		short = "Ignore comment suppresses nothing"
+		full = "The ignore comment can be removed, as the resource has no findings it covers."
+		help = "Remove the ignore comment."
+		helpMarkdown = help
+	case shared.RuleUnattachedIgnore:
+		short = "Ignore comment is not on a resource"
+		full = "Ignore comments must be directly above a resource, on its first line, or at the top of its body."
+		help = "Move the ignore comment onto the resource it is meant for, or remove it."
+		helpMarkdown = help
+	case shared.RuleParseError:
+		short = "File could not be parsed"
+		full = "The file could not be read or parsed, so its resources were not checked for tags."
+		help = "Fix the syntax error, or skip the file with --skip."
+		helpMarkdown = "Fix the syntax error, or skip the file with `--skip`."
+	default:
+		short = ruleID
+		full = ruleID
+	}
+
+	rule := sarifRule{
+		ID:               ruleID,
+		Name:             ruleID,
+		ShortDescription: sarifMessage{Text: short},
+		FullDescription:  sarifMessage{Text: full},
+		Help: sarifHelp{
+			Text:     strings.TrimSpace(full + " " + help),
+			Markdown: strings.TrimSpace(full + "\n\n" + helpMarkdown),
+		},
+	}
+	if finding.RuleID != shared.RuleParseError {
+		rule.DeprecatedIDs = []string{sarifLegacyRuleID} // each rule takes over part of the old one
+	}
+	rule.DefaultConfiguration.Level = sarifLevel(finding.Severity)
+	return rule
+}
+
+// sarifTagFix is a suggested edit adding a missing tag, when the scanner found where it can go
+func sarifTagFix(v shared.Violation, finding shared.Finding) (sarifFix, bool) {
+	if v.Fix == nil || finding.RuleID != shared.RuleMissingTag {
+		return sarifFix{}, false
+	}
+
+	value := ""
+	if len(finding.Expected) > 0 {
+		value = finding.Expected[0]
+	}
+
+	replacement := sarifReplacement{DeletedRegion: sarifRegion{StartLine: v.Fix.Line, StartColumn: 1, EndLine: v.Fix.Line, EndColumn: 1}}
+	replacement.InsertedContent.Text = v.Fix.Text(finding.Tag, value)
+
+	return sarifFix{
+		Description: sarifMessage{Text: fmt.Sprintf("Add the %s tag", finding.Tag)},
+		ArtifactChanges: []sarifArtifactChange{{
+			ArtifactLocation: sarifArtifactLocation{URI: relativePath(v.FilePath)},
+			Replacements:     []sarifReplacement{replacement},
+		}},
+	}, true
+}
+
+// sarifLevel maps a severity to a SARIF result level
+func sarifLevel(severity shared.Severity) string {
+	switch severity {
+	case shared.SeverityWarning:
+		return "warning"
+	case shared.SeverityInfo:
+		return "note"
+	default:
+		return "error"
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"testing"
 
+	"github.com/google/go-cmp/cmp"
 	"github.com/jakebark/tag-nag/internal/shared"
 )
 
@@ -13,6 +14,7 @@ func TestSARIFFormatter_Format(t *testing.T) {
 		violations   []shared.Violation
 		wantResults  int
 		wantFailures int
+		wantLevels   []string
 	}{
 		{
 			name:         "empty violations",
@@ -26,7 +28,7 @@ func TestSARIFFormatter_Format(t *testing.T) {
 				{
 					ResourceType: "aws_s3_bucket",
 					ResourceName: "test",
-					MissingTags:  []string{"Owner"},
+					Findings:     missingTags("Owner"),
 					FilePath:     "main.tf",
 					Line:         10,
 				},
@@ -37,8 +39,8 @@ func TestSARIFFormatter_Format(t *testing.T) {
 		{
 			name: "multiple violations",
 			violations: []shared.Violation{
-				{ResourceType: "aws_s3_bucket", ResourceName: "test1", MissingTags: []string{"Owner"}, FilePath: "main.tf", Line: 1},
-				{ResourceType: "aws_instance", ResourceName: "test2", MissingTags: []string{"Env"}, FilePath: "main.tf", Line: 20},
+				{ResourceType: "aws_s3_bucket", ResourceName: "test1", Findings: missingTags("Owner"), FilePath: "main.tf", Line: 1},
+				{ResourceType: "aws_instance", ResourceName: "test2", Findings: missingTags("Env"), FilePath: "main.tf", Line: 20},
 			},
 			wantResults:  2,
 			wantFailures: 2,
@@ -46,7 +48,7 @@ func TestSARIFFormatter_Format(t *testing.T) {
 		{
 			name: "skipped violation",
 			violations: []shared.Violation{
-				{ResourceType: "aws_s3_bucket", ResourceName: "test", Skip: true, FilePath: "main.tf", Line: 1},
+				{ResourceType: "aws_s3_bucket", ResourceName: "test", Findings: suppressedTags("Owner"), Skip: true, FilePath: "main.tf", Line: 1},
 			},
 			wantResults:  1,
 			wantFailures: 0,
@@ -54,18 +56,29 @@ func TestSARIFFormatter_Format(t *testing.T) {
 		{
 			name: "mixed violations",
 			violations: []shared.Violation{
-				{ResourceType: "aws_s3_bucket", ResourceName: "test1", MissingTags: []string{"Owner"}, FilePath: "main.tf", Line: 1},
-				{ResourceType: "aws_instance", ResourceName: "test2", Skip: true, FilePath: "main.tf", Line: 10},
+				{ResourceType: "aws_s3_bucket", ResourceName: "test1", Findings: missingTags("Owner"), FilePath: "main.tf", Line: 1},
+				{ResourceType: "aws_instance", ResourceName: "test2", Findings: suppressedTags("Owner"), Skip: true, FilePath: "main.tf", Line: 10},
 			},
 			wantResults:  2,
 			wantFailures: 1,
 		},
+		{
+			name: "severity levels",
+			violations: []shared.Violation{
+				{ResourceType: "aws_s3_bucket", ResourceName: "test1", Findings: missingTags("Owner"), FilePath: "main.tf", Line: 1},
+				{ResourceType: "aws_instance", ResourceName: "test2", Findings: warningTags("CostCenter"), FilePath: "main.tf", Line: 10},
+				{ResourceType: "aws_instance", ResourceName: "test3", Findings: []shared.Finding{{RuleID: shared.RuleMissingTag, Tag: "Team", Severity: shared.SeverityInfo}}, FilePath: "main.tf", Line: 20},
+			},
+			wantResults:  3,
+			wantFailures: 3,
+			wantLevels:   []string{"error", "warning", "note"},
+		},
 	}
 
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
 			formatter := &SARIFFormatter{}
-			output, err := formatter.Format(tc.violations)
+			output, err := formatter.Format(tc.violations, nil)
 
 			if err != nil {
 				t.Errorf("Format() error = %v", err)
@@ -93,13 +106,157 @@ func TestSARIFFormatter_Format(t *testing.T) {
 
 			failures := 0
 			for _, r := range results {
-				if r.Kind == "fail" {
+				if r.Kind == "fail" && len(r.Suppressions) == 0 {
 					failures++
 				}
 			}
 			if failures != tc.wantFailures {
 				t.Errorf("Failure count = %d; want %d", failures, tc.wantFailures)
 			}
+
+			for i, level := range tc.wantLevels {
+				if results[i].Level != level {
+					t.Errorf("Result %d level = %q; want %q", i, results[i].Level, level)
+				}
+			}
 		})
 	}
 }
+
+func TestSARIFFormatter_Details(t *testing.T) {
+	violations := []shared.Violation{
+		{
+			ResourceType: "aws_s3_bucket",
+			ResourceName: "test",
+			Findings: []shared.Finding{
+				{RuleID: shared.RuleMissingTag, Tag: "Owner", Severity: shared.SeverityError},
+				{RuleID: shared.RuleInvalidTagValue, Tag: "Environment", Expected: []string{"Dev", "Prod"}, Actual: "qa", Severity: shared.SeverityWarning, Line: 6},
+			},
+			FilePath: "main.tf",
+			Line:     3,
+			EndLine:  8,
+			Fix:      &shared.TagFix{Line: 8, Indent: "  ", Style: shared.FixHCLAttribute},
+		},
+		{ResourceType: "aws_instance", ResourceName: "old", Findings: suppressedTags("Owner"), Skip: true, SkipReason: "legacy", FilePath: "main.tf", Line: 10, EndLine: 12},
+	}
+
+	output, err := (&SARIFFormatter{RequiredTags: []string{"Owner", "Project"}}).Format(violations, nil)
+	if err != nil {
+		t.Fatalf("Format() error = %v", err)
+	}
+
+	var parsed sarifOutput
+	if err := json.Unmarshal(output, &parsed); err != nil {
+		t.Fatalf("Output is not valid JSON: %v", err)
+	}
+	run := parsed.Runs[0]
+
+	var ruleIDs []string
+	for _, rule := range run.Tool.Driver.Rules {
+		ruleIDs = append(ruleIDs, rule.ID)
+		if rule.ShortDescription.Text == "" || rule.Help.Text == "" {
+			t.Errorf("Rule %s has no description or help", rule.ID)
+		}
+	}
+	if diff := cmp.Diff([]string{"missing-tag/Owner", "missing-tag/Project", "invalid-tag-value/Environment"}, ruleIDs); diff != "" {
+		t.Errorf("Rules mismatch (-want +got):\n%s", diff)
+	}
+
+	if len(run.Results) != 3 {
+		t.Fatalf("Results count = %d; want 3", len(run.Results))
+	}
+	for i, r := range run.Results {
+		if run.Tool.Driver.Rules[r.RuleIndex].ID != r.RuleID {
+			t.Errorf("Result %d ruleIndex %d does not point at %s", i, r.RuleIndex, r.RuleID)
+		}
+		if r.PartialFingerprints["tagNagFinding/v1"] == "" {
+			t.Errorf("Result %d has no partial fingerprint", i)
+		}
+	}
+
+	missing := run.Results[0]
+	if region := missing.Locations[0].PhysicalLocation.Region; region.StartLine != 3 || region.EndLine != 8 {
+		t.Errorf("Region = %+v; want lines 3-8", region)
+	}
+	if len(missing.Fixes) != 1 {
+		t.Fatalf("Fixes count = %d; want 1", len(missing.Fixes))
+	}
+	replacement := missing.Fixes[0].ArtifactChanges[0].Replacements[0]
+	if replacement.DeletedRegion.StartLine != 8 || replacement.InsertedContent.Text != "  tags = {\n    Owner = \"\"\n  }\n" {
+		t.Errorf("Fix = %+v", replacement)
+	}
+
+	invalid := run.Results[1]
+	if len(invalid.Fixes) != 0 || invalid.Level != "warning" {
+		t.Errorf("Invalid value result = %+v; want a warning with no fix", invalid)
+	}
+	if region := invalid.Locations[0].PhysicalLocation.Region; region != (sarifRegion{StartLine: 6}) {
+		t.Errorf("Region = %+v; want line 6", region)
+	}
+
+	suppressed := run.Results[2]
+	if diff := cmp.Diff([]sarifSuppression{{Kind: "inSource", Justification: "legacy"}}, suppressed.Suppressions); diff != "" {
+		t.Errorf("Suppressions mismatch (-want +got):\n%s", diff)
+	}
+	if suppressed.Kind != "notApplicable" || suppressed.Level != "" {
+		t.Errorf("Suppressed result kind = %q, level = %q; want notApplicable with no level", suppressed.Kind, suppressed.Level)
+	}
+}
+
+func TestSARIFFormatter_Rules(t *testing.T) {
+	violations := []shared.Violation{
+		{ResourceType: "aws_s3_bucket", ResourceName: "test", Findings: []shared.Finding{
+			{RuleID: shared.RuleMissingTag, Tag: "Owner", Severity: shared.SeverityError},
+			{RuleID: shared.RuleIgnoreWithoutReason, Severity: shared.SeverityInfo},
+		}, FilePath: "main.tf", Line: 3},
+		shared.NewParseError("broken.tf", 2, "Unclosed configuration block", false).Violation(),
+	}
+
+	formatter := &SARIFFormatter{RequiredTags: []string{"CostCenter", "Owner"}, Severities: map[string]shared.Severity{"CostCenter": shared.SeverityWarning}}
+	output, err := formatter.Format(violations, nil)
+	if err != nil {
+		t.Fatalf("Format() error = %v", err)
+	}
+
+	var parsed sarifOutput
+	if err := json.Unmarshal(output, &parsed); err != nil {
+		t.Fatalf("Output is not valid JSON: %v", err)
+	}
+
+	levels := make(map[string]string)
+	helps := make(map[string]bool)
+	for _, rule := range parsed.Runs[0].Tool.Driver.Rules {
+		levels[rule.ID] = rule.DefaultConfiguration.Level
+		if helps[rule.Help.Text] {
+			t.Errorf("Rule %s repeats the help of another rule: %q", rule.ID, rule.Help.Text)
+		}
+		helps[rule.Help.Text] = true
+
+		wantDeprecated := []string{"missing-tags"}
+		if rule.ID == shared.RuleParseError {
+			wantDeprecated = nil
+		}
+		if diff := cmp.Diff(wantDeprecated, rule.DeprecatedIDs); diff != "" {
+			t.Errorf("Rule %s deprecatedIds mismatch (-want +got):\n%s", rule.ID, diff)
+		}
+	}
+	expected := map[string]string{
+		"missing-tag/CostCenter":       "warning",
+		"missing-tag/Owner":            "error",
+		shared.RuleIgnoreWithoutReason: "note",
+		shared.RuleParseError:          "warning",
+	}
+	if diff := cmp.Diff(expected, levels); diff != "" {
+		t.Errorf("Rule levels mismatch (-want +got):\n%s", diff)
+	}
+}
+
+// suppressedTags builds missing-tag findings suppressed by an ignore comment, for formatter tests
+func suppressedTags(tags ...string) []shared.Finding {
+	findings := missingTags(tags...)
+	for i := range findings {
+		findings[i].Suppressed = true
+		findings[i].Reason = "legacy"
+	}
+	return findings
+}
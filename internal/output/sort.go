@@ -0,0 +1,53 @@
+package output
+
+import (
+	"sort"
+
+	"github.com/jakebark/tag-nag/internal/shared"
+)
+
+// sortViolations returns a copy of the violations in a stable order, so reports diff cleanly between runs
+func sortViolations(violations []shared.Violation, sortBy shared.SortOrder) []shared.Violation {
+	sorted := append([]shared.Violation(nil), violations...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		a, b := sorted[i], sorted[j]
+		switch sortBy {
+		case shared.SortByType:
+			if a.ResourceType != b.ResourceType {
+				return a.ResourceType < b.ResourceType
+			}
+		case shared.SortByTag:
+			if tagA, tagB := firstTag(a), firstTag(b); tagA != tagB {
+				return tagA < tagB
+			}
+		}
+		if a.FilePath != b.FilePath {
+			return a.FilePath < b.FilePath
+		}
+		if a.Line != b.Line {
+			return a.Line < b.Line
+		}
+		return resourceAddress(a) < resourceAddress(b)
+	})
+	return sorted
+}
+
+// sortResources returns a copy of the resources ordered by file and line
+func sortResources(resources []shared.Resource) []shared.Resource {
+	sorted := append([]shared.Resource(nil), resources...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].FilePath != sorted[j].FilePath {
+			return sorted[i].FilePath < sorted[j].FilePath
+		}
+		return sorted[i].Line < sorted[j].Line
+	})
+	return sorted
+}
+
+// firstTag is the first tag a violation's findings name, "" for file-level violations
+func firstTag(v shared.Violation) string {
+	if len(v.Findings) == 0 {
+		return ""
+	}
+	return v.Findings[0].Tag
+}
@@ -0,0 +1,44 @@
+package output
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/jakebark/tag-nag/internal/shared"
+)
+
+func TestSortViolations(t *testing.T) {
+	violations := []shared.Violation{
+		{ResourceType: "aws_s3_bucket", ResourceName: "b", Findings: missingTags("Project"), FilePath: "main.tf", Line: 9},
+		{ResourceType: "aws_instance", ResourceName: "web", Findings: missingTags("Owner"), FilePath: "z.tf", Line: 1},
+		{Findings: []shared.Finding{{RuleID: shared.RuleUnattachedIgnore}}, FilePath: "main.tf", Line: 30},
+		{ResourceType: "aws_s3_bucket", ResourceName: "a", Findings: missingTags("Owner"), FilePath: "main.tf", Line: 2},
+	}
+
+	testCases := []struct {
+		name     string
+		sortBy   shared.SortOrder
+		expected []string
+	}{
+		{name: "default", expected: []string{"aws_s3_bucket.a", "aws_s3_bucket.b", "file", "aws_instance.web"}},
+		{name: "file", sortBy: shared.SortByFile, expected: []string{"aws_s3_bucket.a", "aws_s3_bucket.b", "file", "aws_instance.web"}},
+		{name: "type", sortBy: shared.SortByType, expected: []string{"file", "aws_instance.web", "aws_s3_bucket.a", "aws_s3_bucket.b"}},
+		{name: "tag", sortBy: shared.SortByTag, expected: []string{"file", "aws_s3_bucket.a", "aws_instance.web", "aws_s3_bucket.b"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var got []string
+			for _, v := range sortViolations(violations, tc.sortBy) {
+				got = append(got, resourceAddress(v))
+			}
+			if diff := cmp.Diff(tc.expected, got); diff != "" {
+				t.Errorf("sortViolations() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+
+	if resourceAddress(violations[0]) != "aws_s3_bucket.b" {
+		t.Error("sortViolations() should not reorder its input")
+	}
+}
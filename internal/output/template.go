@@ -0,0 +1,113 @@
+package output
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/jakebark/tag-nag/internal/shared"
+)
+
+// TemplateFormatter renders violations through a user-supplied text/template, eg for Slack messages or tickets
+type TemplateFormatter struct {
+	Path         string
+	RequiredTags []string
+}
+
+// TemplateData is the data available to a template
+type TemplateData struct {
+	Violations []shared.Violation
+	Resources  []shared.Resource
+	Summary    Summary
+	Coverage   Coverage
+}
+
+// TemplateGroup is a set of violations sharing a key, see the group template function
+type TemplateGroup struct {
+	Key        string
+	Violations []shared.Violation
+}
+
+// templateFuncs are the helper functions available to templates
+var templateFuncs = template.FuncMap{
+	"join":     func(items []string, sep string) string { return strings.Join(items, sep) },
+	"group":    groupForTemplate,
+	"relPath":  relativePath,
+	"address":  resourceAddress,
+	"describe": describeViolation,
+	"finding":  describeFinding,
+}
+
+// Format renders the template at Path
+func (f *TemplateFormatter) Format(violations []shared.Violation, resources []shared.Resource) ([]byte, error) {
+	if f.Path == "" {
+		return nil, fmt.Errorf("no template given, use --template")
+	}
+	text, err := os.ReadFile(f.Path)
+	if err != nil {
+		return nil, fmt.Errorf("reading template: %w", err)
+	}
+
+	tmpl, err := template.New(filepath.Base(f.Path)).Funcs(templateFuncs).Option("missingkey=error").Parse(string(text))
+	if err != nil {
+		return nil, fmt.Errorf("parsing template: %w", err)
+	}
+
+	data := TemplateData{
+		Violations: violations,
+		Resources:  resources,
+		Summary:    summarize(violations),
+		Coverage:   measureCoverage(violations, resources, f.RequiredTags),
+	}
+
+	var output bytes.Buffer
+	if err := tmpl.Execute(&output, data); err != nil {
+		return nil, fmt.Errorf("rendering template: %w", err)
+	}
+
+	return output.Bytes(), nil
+}
+
+// groupForTemplate groups violations by "file", "type" or "tag", in key order
+// a violation is in each tag group its findings name
+func groupForTemplate(by string, violations []shared.Violation) ([]TemplateGroup, error) {
+	var keys func(shared.Violation) []string
+	switch by {
+	case "file":
+		keys = func(v shared.Violation) []string { return []string{relativePath(v.FilePath)} }
+	case "type":
+		keys = func(v shared.Violation) []string { return []string{v.ResourceType} }
+	case "tag":
+		keys = func(v shared.Violation) []string {
+			var tags []string
+			seen := make(map[string]bool)
+			for _, finding := range v.Findings {
+				if !seen[finding.Tag] {
+					seen[finding.Tag] = true
+					tags = append(tags, finding.Tag)
+				}
+			}
+			return tags
+		}
+	default:
+		return nil, fmt.Errorf("cannot group by %q, use file, type or tag", by)
+	}
+
+	grouped := make(map[string][]shared.Violation)
+	for _, v := range violations {
+		for _, key := range keys(v) {
+			grouped[key] = append(grouped[key], v)
+		}
+	}
+
+	var groups []TemplateGroup
+	for key, members := range grouped {
+		groups = append(groups, TemplateGroup{Key: key, Violations: members})
+	}
+	sort.Slice(groups, func(i, j int) bool { return groups[i].Key < groups[j].Key })
+	return groups, nil
+}
@@ -0,0 +1,88 @@
+package output
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jakebark/tag-nag/internal/shared"
+)
+
+func TestTemplateFormatter_Format(t *testing.T) {
+	violations := []shared.Violation{
+		{ResourceType: "aws_s3_bucket", ResourceName: "b", Findings: append(missingTags("Owner", "Project"), shared.Finding{RuleID: shared.RuleTagKeyStyle, Tag: "Owner", Pattern: "kebab-case"}), MissingTags: []string{"Owner", "Project"}, FilePath: "z.tf", Line: 4},
+		{ResourceType: "aws_instance", ResourceName: "web", Findings: missingTags("Owner"), MissingTags: []string{"Owner"}, FilePath: "main.tf", Line: 20},
+	}
+	resources := []shared.Resource{
+		{ResourceType: "aws_s3_bucket", ResourceName: "b", FilePath: "z.tf"},
+		{ResourceType: "aws_instance", ResourceName: "web", FilePath: "main.tf"},
+		{ResourceType: "aws_s3_bucket", ResourceName: "a", FilePath: "main.tf", Compliant: true},
+		{ResourceType: "aws_s3_bucket", ResourceName: "c", FilePath: "main.tf", Compliant: true},
+	}
+
+	testCases := []struct {
+		name          string
+		template      string
+		expected      string
+		expectedError string
+	}{
+		{
+			name:     "summary and coverage",
+			template: `{{.Summary.Total}} violations, {{.Coverage.Compliant}}/{{.Coverage.Resources}} compliant`,
+			expected: "2 violations, 2/4 compliant",
+		},
+		{
+			name:     "join and address",
+			template: `{{range .Violations}}{{address .}}: {{join .MissingTags ", "}}; {{end}}`,
+			expected: "aws_s3_bucket.b: Owner, Project; aws_instance.web: Owner; ",
+		},
+		{
+			name:     "group by file",
+			template: `{{range group "file" .Violations}}{{.Key}}={{len .Violations}} {{end}}`,
+			expected: "main.tf=1 z.tf=1 ",
+		},
+		{
+			name:     "group by tag",
+			template: `{{range group "tag" .Violations}}{{.Key}}:{{range .Violations}} {{address .}}{{end}}; {{end}}`,
+			expected: "Owner: aws_s3_bucket.b aws_instance.web; Project: aws_s3_bucket.b; ",
+		},
+		{
+			name:          "invalid group",
+			template:      `{{range group "severity" .Violations}}{{end}}`,
+			expectedError: `cannot group by "severity"`,
+		},
+		{
+			name:          "parse error",
+			template:      `{{range .Violations}}`,
+			expectedError: "parsing template",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "report.tmpl")
+			if err := os.WriteFile(path, []byte(tc.template), 0644); err != nil {
+				t.Fatalf("Failed to write template: %v", err)
+			}
+
+			output, err := (&TemplateFormatter{Path: path}).Format(violations, resources)
+			if tc.expectedError != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.expectedError) {
+					t.Fatalf("Format() error = %v, want %q", err, tc.expectedError)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Format() error = %v", err)
+			}
+			if string(output) != tc.expected {
+				t.Errorf("Format() = %q, want %q", output, tc.expected)
+			}
+		})
+	}
+
+	if _, err := (&TemplateFormatter{}).Format(violations, resources); err == nil {
+		t.Error("Format() without a template path should fail")
+	}
+}
@@ -2,35 +2,136 @@ package output
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 
 	"github.com/jakebark/tag-nag/internal/shared"
 )
 
-type TextFormatter struct{}
+type TextFormatter struct {
+	SortBy       shared.SortOrder // groups violations by file (default), resource type or tag
+	RequiredTags []string         // for the coverage footer
+}
 
 // Format formats violations as human-readable text
-func (f *TextFormatter) Format(violations []shared.Violation) ([]byte, error) {
+func (f *TextFormatter) Format(violations []shared.Violation, resources []shared.Resource) ([]byte, error) {
 	var output strings.Builder
 
-	fileGroups := make(map[string][]shared.Violation)
-	for _, v := range violations {
-		fileGroups[v.FilePath] = append(fileGroups[v.FilePath], v)
+	switch f.SortBy {
+	case shared.SortByType:
+		for _, group := range groupViolations(sortViolations(violations, f.SortBy), func(v shared.Violation) string { return v.ResourceType }) {
+			heading := group.key
+			if heading == "" {
+				heading = "files"
+			}
+			output.WriteString(fmt.Sprintf("\nViolation(s) on %s\n", heading))
+			for _, v := range group.violations {
+				output.WriteString(fmt.Sprintf("  %s:%d: %s\n", v.FilePath, v.Line, describeTextViolation(v)))
+			}
+		}
+	case shared.SortByTag:
+		writeTagGroups(&output, sortViolations(violations, shared.SortByFile))
+	default:
+		for _, group := range groupViolations(sortViolations(violations, shared.SortByFile), func(v shared.Violation) string { return v.FilePath }) {
+			output.WriteString(fmt.Sprintf("\nViolation(s) in %s\n", group.key))
+			for _, v := range group.violations {
+				output.WriteString(fmt.Sprintf("  %d: %s\n", v.Line, describeTextViolation(v)))
+			}
+		}
 	}
 
-	for filePath, fileViolations := range fileGroups {
-		output.WriteString(fmt.Sprintf("\nViolation(s) in %s\n", filePath))
+	writeCoverage(&output, measureCoverage(violations, resources, f.RequiredTags))
+
+	return []byte(output.String()), nil
+}
 
-		for _, v := range fileViolations {
+// writeCoverage is the footer of compliance overall, per required tag, and by resource type and directory
+func writeCoverage(output *strings.Builder, coverage Coverage) {
+	if coverage.Resources == 0 {
+		return
+	}
+
+	output.WriteString(fmt.Sprintf("\nCoverage: %d/%d resource(s) compliant (%.1f%%)\n", coverage.Compliant, coverage.Resources, coverage.Percent))
+	for _, tag := range coverage.Tags {
+		output.WriteString(fmt.Sprintf("  %s: %d present, %d absent, %d invalid\n", tag.Tag, tag.Present, tag.Absent, tag.Invalid))
+	}
+
+	for _, section := range []struct {
+		heading string
+		groups  map[string]Coverage
+	}{
+		{"resource type", coverage.ByType},
+		{"directory", coverage.ByDirectory},
+	} {
+		keys := make([]string, 0, len(section.groups))
+		for key := range section.groups {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		output.WriteString(fmt.Sprintf("\nCoverage by %s\n", section.heading))
+		for _, key := range keys {
+			group := section.groups[key]
+			output.WriteString(fmt.Sprintf("  %s: %d/%d compliant (%.1f%%)\n", key, group.Compliant, group.Resources, group.Percent))
+		}
+	}
+}
+
+// describeTextViolation is a resource and its issues, eg `aws_s3_bucket "this" 🏷️  Missing tags: Owner`
+func describeTextViolation(v shared.Violation) string {
+	if v.Skip {
+		skipped := "skipped"
+		if v.SkipReason != "" {
+			skipped += " " + describeIgnored(v.SkipReason)
+		}
+		return fmt.Sprintf("%s %s", describeResource(v), skipped)
+	}
+	return fmt.Sprintf("%s 🏷️  %s", describeResource(v), describeViolation(v))
+}
+
+// writeTagGroups lists each finding under the tag it names, so a report can be read one tag at a time
+func writeTagGroups(output *strings.Builder, violations []shared.Violation) {
+	tagLines := make(map[string][]string)
+	var tags []string
+	for _, v := range violations {
+		for _, finding := range v.Findings {
+			if _, seen := tagLines[finding.Tag]; !seen {
+				tags = append(tags, finding.Tag)
+			}
+			line := fmt.Sprintf("  %s:%d: %s 🏷️  %s", v.FilePath, v.FindingLine(finding), describeResource(v), describeFinding(finding))
 			if v.Skip {
-				output.WriteString(fmt.Sprintf("  %d: %s \"%s\" skipped\n",
-					v.Line, v.ResourceType, v.ResourceName))
-			} else {
-				output.WriteString(fmt.Sprintf("  %d: %s \"%s\" 🏷️  Missing tags: %s\n",
-					v.Line, v.ResourceType, v.ResourceName, strings.Join(v.MissingTags, ", ")))
+				line = fmt.Sprintf("  %s:%d: %s %s", v.FilePath, v.FindingLine(finding), describeResource(v), describeFinding(finding))
 			}
+			tagLines[finding.Tag] = append(tagLines[finding.Tag], line)
 		}
 	}
+	sort.Strings(tags)
 
-	return []byte(output.String()), nil
+	for _, tag := range tags {
+		heading := tag
+		if heading == "" {
+			heading = "ignore comments"
+		}
+		output.WriteString(fmt.Sprintf("\nViolation(s) of %s\n", heading))
+		for _, line := range tagLines[tag] {
+			output.WriteString(line + "\n")
+		}
+	}
+}
+
+type violationGroup struct {
+	key        string
+	violations []shared.Violation
+}
+
+// groupViolations splits sorted violations into runs sharing a key
+func groupViolations(violations []shared.Violation, key func(shared.Violation) string) []violationGroup {
+	var groups []violationGroup
+	for _, v := range violations {
+		if len(groups) == 0 || groups[len(groups)-1].key != key(v) {
+			groups = append(groups, violationGroup{key: key(v)})
+		}
+		groups[len(groups)-1].violations = append(groups[len(groups)-1].violations, v)
+	}
+	return groups
 }
@@ -9,14 +9,14 @@ import (
 
 func TestTextFormatter_Format(t *testing.T) {
 	testCases := []struct {
-		name           string
-		violations     []shared.Violation
-		wantContains   []string
+		name            string
+		violations      []shared.Violation
+		wantContains    []string
 		wantNotContains []string
 	}{
 		{
-			name:       "empty violations",
-			violations: []shared.Violation{},
+			name:         "empty violations",
+			violations:   []shared.Violation{},
 			wantContains: []string{},
 		},
 		{
@@ -25,7 +25,7 @@ func TestTextFormatter_Format(t *testing.T) {
 				{
 					ResourceType: "aws_s3_bucket",
 					ResourceName: "test",
-					MissingTags:  []string{"Owner"},
+					Findings:     missingTags("Owner"),
 					FilePath:     "main.tf",
 					Line:         10,
 				},
@@ -39,8 +39,8 @@ func TestTextFormatter_Format(t *testing.T) {
 		{
 			name: "multiple violations same file",
 			violations: []shared.Violation{
-				{ResourceType: "aws_s3_bucket", ResourceName: "test1", MissingTags: []string{"Owner"}, FilePath: "main.tf", Line: 5},
-				{ResourceType: "aws_instance", ResourceName: "test2", MissingTags: []string{"Env"}, FilePath: "main.tf", Line: 15},
+				{ResourceType: "aws_s3_bucket", ResourceName: "test1", Findings: missingTags("Owner"), FilePath: "main.tf", Line: 5},
+				{ResourceType: "aws_instance", ResourceName: "test2", Findings: missingTags("Env"), FilePath: "main.tf", Line: 15},
 			},
 			wantContains: []string{
 				"Violation(s) in main.tf",
@@ -63,10 +63,32 @@ func TestTextFormatter_Format(t *testing.T) {
 				"Missing tags:",
 			},
 		},
+		{
+			name: "key issues",
+			violations: []shared.Violation{
+				{ResourceType: "aws_s3_bucket", ResourceName: "test", Findings: []shared.Finding{{RuleID: shared.RuleDuplicateTagKey, Tag: "Owner/owner", Severity: shared.SeverityError}}, FilePath: "main.tf", Line: 10},
+			},
+			wantContains: []string{
+				"10: aws_s3_bucket \"test\"",
+				"Key issues: Owner/owner (case duplicate)",
+			},
+			wantNotContains: []string{
+				"Missing tags:",
+			},
+		},
+		{
+			name: "warning violation",
+			violations: []shared.Violation{
+				{ResourceType: "aws_s3_bucket", ResourceName: "test", Findings: warningTags("CostCenter"), FilePath: "main.tf", Line: 10},
+			},
+			wantContains: []string{
+				"Missing tags: CostCenter [warning]",
+			},
+		},
 		{
 			name: "mixed violations",
 			violations: []shared.Violation{
-				{ResourceType: "aws_s3_bucket", ResourceName: "test1", MissingTags: []string{"Owner"}, FilePath: "main.tf", Line: 5},
+				{ResourceType: "aws_s3_bucket", ResourceName: "test1", Findings: missingTags("Owner"), FilePath: "main.tf", Line: 5},
 				{ResourceType: "aws_instance", ResourceName: "test2", FilePath: "main.tf", Line: 15, Skip: true},
 			},
 			wantContains: []string{
@@ -81,7 +103,7 @@ func TestTextFormatter_Format(t *testing.T) {
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
 			formatter := &TextFormatter{}
-			output, err := formatter.Format(tc.violations)
+			output, err := formatter.Format(tc.violations, nil)
 
 			if err != nil {
 				t.Errorf("Format() error = %v", err)
@@ -103,4 +125,89 @@ func TestTextFormatter_Format(t *testing.T) {
 			}
 		})
 	}
-}
\ No newline at end of file
+}
+
+func TestTextFormatter_SortBy(t *testing.T) {
+	violations := []shared.Violation{
+		{ResourceType: "aws_s3_bucket", ResourceName: "b", Findings: missingTags("Owner", "Project"), FilePath: "z.tf", Line: 4},
+		{ResourceType: "aws_instance", ResourceName: "web", Findings: missingTags("Owner"), FilePath: "main.tf", Line: 20},
+		{ResourceType: "aws_s3_bucket", ResourceName: "a", Findings: missingTags("Project"), FilePath: "main.tf", Line: 2},
+	}
+
+	testCases := []struct {
+		name     string
+		sortBy   shared.SortOrder
+		expected string
+	}{
+		{
+			name:   "file",
+			sortBy: shared.SortByFile,
+			expected: "\nViolation(s) in main.tf\n" +
+				"  2: aws_s3_bucket \"a\" 🏷️  Missing tags: Project\n" +
+				"  20: aws_instance \"web\" 🏷️  Missing tags: Owner\n" +
+				"\nViolation(s) in z.tf\n" +
+				"  4: aws_s3_bucket \"b\" 🏷️  Missing tags: Owner, Project\n",
+		},
+		{
+			name:   "type",
+			sortBy: shared.SortByType,
+			expected: "\nViolation(s) on aws_instance\n" +
+				"  main.tf:20: aws_instance \"web\" 🏷️  Missing tags: Owner\n" +
+				"\nViolation(s) on aws_s3_bucket\n" +
+				"  main.tf:2: aws_s3_bucket \"a\" 🏷️  Missing tags: Project\n" +
+				"  z.tf:4: aws_s3_bucket \"b\" 🏷️  Missing tags: Owner, Project\n",
+		},
+		{
+			name:   "tag",
+			sortBy: shared.SortByTag,
+			expected: "\nViolation(s) of Owner\n" +
+				"  main.tf:20: aws_instance \"web\" 🏷️  Owner\n" +
+				"  z.tf:4: aws_s3_bucket \"b\" 🏷️  Owner\n" +
+				"\nViolation(s) of Project\n" +
+				"  main.tf:2: aws_s3_bucket \"a\" 🏷️  Project\n" +
+				"  z.tf:4: aws_s3_bucket \"b\" 🏷️  Project\n",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			output, err := (&TextFormatter{SortBy: tc.sortBy}).Format(violations, nil)
+			if err != nil {
+				t.Fatalf("Format() error = %v", err)
+			}
+			if string(output) != tc.expected {
+				t.Errorf("Format() = %q, want %q", output, tc.expected)
+			}
+		})
+	}
+}
+
+func TestTextFormatter_Coverage(t *testing.T) {
+	violations := []shared.Violation{
+		{ResourceType: "aws_s3_bucket", ResourceName: "a", Findings: missingTags("Owner"), FilePath: "main.tf", Line: 2},
+	}
+	resources := []shared.Resource{
+		{ResourceType: "aws_s3_bucket", ResourceName: "a", FilePath: "main.tf"},
+		{ResourceType: "aws_instance", ResourceName: "web", FilePath: "modules/web/main.tf", Compliant: true},
+	}
+
+	output, err := (&TextFormatter{RequiredTags: []string{"Environment", "Owner"}}).Format(violations, resources)
+	if err != nil {
+		t.Fatalf("Format() error = %v", err)
+	}
+
+	expected := "\nViolation(s) in main.tf\n" +
+		"  2: aws_s3_bucket \"a\" 🏷️  Missing tags: Owner\n" +
+		"\nCoverage: 1/2 resource(s) compliant (50.0%)\n" +
+		"  Environment: 2 present, 0 absent, 0 invalid\n" +
+		"  Owner: 1 present, 1 absent, 0 invalid\n" +
+		"\nCoverage by resource type\n" +
+		"  aws_instance: 1/1 compliant (100.0%)\n" +
+		"  aws_s3_bucket: 0/1 compliant (0.0%)\n" +
+		"\nCoverage by directory\n" +
+		"  .: 0/1 compliant (0.0%)\n" +
+		"  modules/web: 1/1 compliant (100.0%)\n"
+	if string(output) != expected {
+		t.Errorf("Format() = %q, want %q", output, expected)
+	}
+}
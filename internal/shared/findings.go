@@ -0,0 +1,241 @@
+package shared
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// keyCasePatterns are the supported tag key naming conventions
+var keyCasePatterns = map[string]*regexp.Regexp{
+	"PascalCase": regexp.MustCompile(`^[A-Z][a-zA-Z0-9]*$`),
+	"camelCase":  regexp.MustCompile(`^[a-z][a-zA-Z0-9]*$`),
+	"kebab-case": regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`),
+	"snake_case": regexp.MustCompile(`^[a-z0-9]+(_[a-z0-9]+)*$`),
+}
+
+// CheckTags checks resource tags, layered over any inherited default tags, against the tag rules
+// defaultTags and resourceTags should keep the original case of their keys
+func CheckTags(rules Rules, defaultTags TagMap, resourceTags TagMap, caseInsensitive bool) []Finding {
+	effectiveTags := MergeTags(NormalizeTags(defaultTags, caseInsensitive), NormalizeTags(resourceTags, caseInsensitive))
+
+	findings := checkRequiredTags(rules, effectiveTags, caseInsensitive)
+	for i := range findings {
+		if findings[i].RuleID == RuleInvalidTagValue {
+			_, setOnResource := matchTagKey(findings[i].Tag, resourceTags, caseInsensitive)
+			findings[i].FromDefaultTags = !setOnResource
+		}
+	}
+
+	findings = append(findings, findCaseDuplicates(defaultTags, resourceTags)...)
+	findings = append(findings, checkKeyStyle(defaultTags, resourceTags, rules.KeyStyle)...)
+	return findings
+}
+
+// checkRequiredTags returns a finding for each required tag that is absent or has a disallowed value
+func checkRequiredTags(rules Rules, effectiveTags TagMap, caseInsensitive bool) []Finding {
+	var findings []Finding
+
+	for requiredKey, allowedValues := range rules.RequiredTags {
+		severity := rules.Severities[requiredKey]
+		if severity == "" {
+			severity = SeverityError
+		}
+
+		effectiveValues, keyFound := matchTagKey(requiredKey, effectiveTags, caseInsensitive)
+		if !keyFound {
+			findings = append(findings, Finding{
+				RuleID:   RuleMissingTag,
+				Tag:      requiredKey,
+				Expected: allowedValues,
+				Severity: severity,
+			})
+			continue
+		}
+
+		// if there are tag values required, check them
+		if !matchTagValue(allowedValues, effectiveValues, caseInsensitive) {
+			findings = append(findings, Finding{
+				RuleID:   RuleInvalidTagValue,
+				Tag:      requiredKey,
+				Expected: allowedValues,
+				Actual:   strings.Join(effectiveValues, ","),
+				Severity: severity,
+			})
+		}
+	}
+
+	sort.Slice(findings, func(i, j int) bool { return findings[i].Tag < findings[j].Tag })
+	return findings
+}
+
+// findCaseDuplicates returns a finding for keys that only differ by case, eg "Owner/owner"
+// AWS treats these as distinct tags
+func findCaseDuplicates(defaultTags TagMap, resourceTags TagMap) []Finding {
+	groups := make(map[string][]string)
+	for _, key := range TagKeys(defaultTags, resourceTags) {
+		lowerKey := strings.ToLower(key)
+		groups[lowerKey] = append(groups[lowerKey], key)
+	}
+
+	var findings []Finding
+	for _, group := range groups {
+		if len(group) < 2 {
+			continue
+		}
+		fromDefaultTags := false
+		for _, key := range group {
+			fromDefaultTags = fromDefaultTags || isInherited(key, defaultTags, resourceTags)
+		}
+		findings = append(findings, Finding{
+			RuleID:          RuleDuplicateTagKey,
+			Tag:             strings.Join(group, "/"),
+			FromDefaultTags: fromDefaultTags,
+			Severity:        SeverityError,
+		})
+	}
+
+	sort.Slice(findings, func(i, j int) bool { return findings[i].Tag < findings[j].Tag })
+	return findings
+}
+
+// checkKeyStyle returns a finding for each key that does not follow the key style
+func checkKeyStyle(defaultTags TagMap, resourceTags TagMap, keyStyle KeyStyle) []Finding {
+	var findings []Finding
+
+	severity := keyStyle.Severity
+	if severity == "" {
+		severity = SeverityError
+	}
+
+	for _, key := range TagKeys(defaultTags, resourceTags) {
+		finding := Finding{
+			RuleID:          RuleTagKeyStyle,
+			Tag:             key,
+			FromDefaultTags: isInherited(key, defaultTags, resourceTags),
+			Severity:        severity,
+		}
+
+		name := key
+		if len(keyStyle.Prefixes) > 0 {
+			prefix, rest, found := strings.Cut(key, ":")
+			if !found || !slices.Contains(keyStyle.Prefixes, prefix) {
+				finding.Pattern = fmt.Sprintf("[%s]:Name", strings.Join(keyStyle.Prefixes, ","))
+				finding.Prefixes = keyStyle.Prefixes
+				findings = append(findings, finding)
+				continue
+			}
+			name = rest
+		}
+
+		if pattern, ok := keyCasePatterns[keyStyle.Case]; ok && !pattern.MatchString(name) {
+			finding.Pattern = keyStyle.Case
+			findings = append(findings, finding)
+		}
+	}
+	return findings
+}
+
+// isInherited reports whether a key is only set by the default tags
+func isInherited(key string, defaultTags TagMap, resourceTags TagMap) bool {
+	_, inDefaults := defaultTags[key]
+	_, onResource := resourceTags[key]
+	return inDefaults && !onResource
+}
+
+// String returns a short description of the finding, eg "Env[Prod]" or "env (not PascalCase)"
+func (f Finding) String() string {
+	switch f.RuleID {
+	case RuleMissingTag, RuleInvalidTagValue:
+		if len(f.Expected) > 0 {
+			return fmt.Sprintf("%s[%s]", f.Tag, strings.Join(f.Expected, ","))
+		}
+		return f.Tag
+	case RuleDuplicateTagKey:
+		return fmt.Sprintf("%s (case duplicate)", f.Tag)
+	case RuleTagKeyStyle:
+		if len(f.Prefixes) > 0 {
+			return fmt.Sprintf("%s (prefix not in [%s])", f.Tag, strings.Join(f.Prefixes, ","))
+		}
+		return fmt.Sprintf("%s (not %s)", f.Tag, f.Pattern)
+	case RuleIgnoreWithoutReason:
+		return "ignore comment has no reason"
+	case RuleExpiredIgnore:
+		return fmt.Sprintf("ignore comment expired %s", f.Actual)
+	case RuleUnusedIgnore:
+		return "ignore comment suppresses nothing"
+	case RuleUnattachedIgnore:
+		return "ignore comment is not on a resource"
+	case RuleParseError:
+		return f.Message
+	default:
+		return f.Tag
+	}
+}
+
+// IsTagRule reports whether the finding is about a required tag, rather than tag keys
+func (f Finding) IsTagRule() bool {
+	return f.RuleID == RuleMissingTag || f.RuleID == RuleInvalidTagValue
+}
+
+// IsKeyRule reports whether the finding is about tag keys
+func (f Finding) IsKeyRule() bool {
+	return f.RuleID == RuleDuplicateTagKey || f.RuleID == RuleTagKeyStyle
+}
+
+// FindingLine returns the line of a finding, its own line when known, otherwise the resource's line
+func (v Violation) FindingLine(f Finding) int {
+	if f.Line > 0 {
+		return f.Line
+	}
+	return v.Line
+}
+
+// MissingTags returns the descriptions of absent tags and disallowed values, eg ["Env[Prod]", "Owner"]
+// suppressed findings are left out
+func MissingTags(findings []Finding) []string {
+	var missingTags []string
+	for _, f := range findings {
+		if f.IsTagRule() && !f.Suppressed {
+			missingTags = append(missingTags, f.String())
+		}
+	}
+	sort.Strings(missingTags)
+	return missingTags
+}
+
+// KeyIssues returns the descriptions of tag key findings, eg ["Owner/owner (case duplicate)"]
+// suppressed findings are left out
+func KeyIssues(findings []Finding) []string {
+	var keyIssues []string
+	for _, f := range findings {
+		if f.IsKeyRule() && !f.Suppressed {
+			keyIssues = append(keyIssues, f.String())
+		}
+	}
+	return keyIssues
+}
+
+// NewParseError reports a file that could not be read or parsed, so its resources were not checked.
+// It is a warning, or an error when strict, and is at line 1 when the line is unknown
+func NewParseError(filePath string, line int, message string, strict bool) ParseError {
+	severity := SeverityWarning
+	if strict {
+		severity = SeverityError
+	}
+	if line < 1 {
+		line = 1
+	}
+	return ParseError{FilePath: filePath, Line: line, Message: message, Severity: severity}
+}
+
+// Violation returns the parse error as a file-level violation with a parse-error finding, so every output reports it
+func (e ParseError) Violation() Violation {
+	return Violation{
+		Line:     e.Line,
+		Findings: []Finding{{RuleID: RuleParseError, Message: e.Message, Severity: e.Severity}},
+		FilePath: e.FilePath,
+	}
+}
@@ -0,0 +1,319 @@
+package shared
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func TestCheckTags(t *testing.T) {
+	testCases := []struct {
+		name            string
+		requiredTags    TagMap
+		severities      map[string]Severity
+		keyStyle        KeyStyle
+		defaultTags     TagMap
+		resourceTags    TagMap
+		caseInsensitive bool
+		expected        []Finding
+	}{
+		{
+			name:         "tags present",
+			requiredTags: TagMap{"Owner": {}, "Env": {"Prod"}},
+			resourceTags: TagMap{"Owner": {"a"}, "Env": {"Prod"}},
+			expected:     nil,
+		},
+		{
+			name:         "missing key",
+			requiredTags: TagMap{"Owner": {}, "Env": {"Prod"}},
+			resourceTags: TagMap{"Env": {"Prod"}},
+			expected: []Finding{
+				{RuleID: RuleMissingTag, Tag: "Owner", Expected: []string{}, Severity: SeverityError},
+			},
+		},
+		{
+			name:         "wrong value",
+			requiredTags: TagMap{"Env": {"Prod"}},
+			resourceTags: TagMap{"Env": {"Dev"}},
+			expected: []Finding{
+				{RuleID: RuleInvalidTagValue, Tag: "Env", Expected: []string{"Prod"}, Actual: "Dev", Severity: SeverityError},
+			},
+		},
+		{
+			name:         "wrong value from default tags",
+			requiredTags: TagMap{"Env": {"Prod"}},
+			defaultTags:  TagMap{"Env": {"Dev"}},
+			resourceTags: TagMap{"Owner": {"a"}},
+			expected: []Finding{
+				{RuleID: RuleInvalidTagValue, Tag: "Env", Expected: []string{"Prod"}, Actual: "Dev", FromDefaultTags: true, Severity: SeverityError},
+			},
+		},
+		{
+			name:         "resource tags override default tags",
+			requiredTags: TagMap{"Env": {"Prod"}},
+			defaultTags:  TagMap{"Env": {"Dev"}},
+			resourceTags: TagMap{"Env": {"Prod"}},
+			expected:     nil,
+		},
+		{
+			name:            "wrong value overridden on resource, case insensitive",
+			requiredTags:    TagMap{"Env": {"Prod"}},
+			defaultTags:     TagMap{"Env": {"Prod"}},
+			resourceTags:    TagMap{"env": {"Dev"}},
+			caseInsensitive: true,
+			expected: []Finding{
+				{RuleID: RuleDuplicateTagKey, Tag: "Env/env", FromDefaultTags: true, Severity: SeverityError},
+				{RuleID: RuleInvalidTagValue, Tag: "Env", Expected: []string{"Prod"}, Actual: "Dev", Severity: SeverityError},
+			},
+		},
+		{
+			name:         "key style",
+			requiredTags: TagMap{"Owner": {}},
+			keyStyle:     KeyStyle{Case: "PascalCase"},
+			defaultTags:  TagMap{"cost-center": {"1"}},
+			resourceTags: TagMap{"Owner": {"a"}},
+			expected: []Finding{
+				{RuleID: RuleTagKeyStyle, Tag: "cost-center", Pattern: "PascalCase", FromDefaultTags: true, Severity: SeverityError},
+			},
+		},
+		{
+			name:         "tag severity",
+			requiredTags: TagMap{"Owner": {}, "CostCenter": {}},
+			severities:   map[string]Severity{"CostCenter": SeverityWarning},
+			resourceTags: TagMap{},
+			expected: []Finding{
+				{RuleID: RuleMissingTag, Tag: "CostCenter", Expected: []string{}, Severity: SeverityWarning},
+				{RuleID: RuleMissingTag, Tag: "Owner", Expected: []string{}, Severity: SeverityError},
+			},
+		},
+		{
+			name:         "key style severity",
+			keyStyle:     KeyStyle{Case: "PascalCase", Severity: SeverityInfo},
+			resourceTags: TagMap{"owner": {"a"}},
+			expected: []Finding{
+				{RuleID: RuleTagKeyStyle, Tag: "owner", Pattern: "PascalCase", Severity: SeverityInfo},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			rules := Rules{RequiredTags: tc.requiredTags, Severities: tc.severities, KeyStyle: tc.keyStyle}
+			actual := CheckTags(rules, tc.defaultTags, tc.resourceTags, tc.caseInsensitive)
+			sortFindings(actual)
+			sortFindings(tc.expected)
+			if !reflect.DeepEqual(actual, tc.expected) {
+				t.Errorf("CheckTags() = %+v; want %+v", actual, tc.expected)
+			}
+		})
+	}
+}
+
+func TestFindCaseDuplicates(t *testing.T) {
+	testCases := []struct {
+		name         string
+		defaultTags  TagMap
+		resourceTags TagMap
+		expected     []string
+	}{
+		{
+			name:         "no duplicates",
+			resourceTags: TagMap{"Owner": {}, "Environment": {}},
+			expected:     nil,
+		},
+		{
+			name:         "case duplicate",
+			resourceTags: TagMap{"Owner": {}, "owner": {}, "Environment": {}},
+			expected:     []string{"Owner/owner (case duplicate)"},
+		},
+		{
+			name:         "exact duplicate in default tags",
+			defaultTags:  TagMap{"Owner": {}},
+			resourceTags: TagMap{"Owner": {}},
+			expected:     nil,
+		},
+		{
+			name:         "case duplicate in default tags",
+			defaultTags:  TagMap{"Owner": {}},
+			resourceTags: TagMap{"owner": {}},
+			expected:     []string{"Owner/owner (case duplicate)"},
+		},
+		{
+			name:         "multiple duplicates",
+			resourceTags: TagMap{"env": {}, "Owner": {}, "OWNER": {}, "Env": {}},
+			expected:     []string{"Env/env (case duplicate)", "OWNER/Owner (case duplicate)"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			actual := KeyIssues(findCaseDuplicates(tc.defaultTags, tc.resourceTags))
+			if !reflect.DeepEqual(actual, tc.expected) {
+				t.Errorf("findCaseDuplicates() = %#v; want %#v", actual, tc.expected)
+			}
+		})
+	}
+}
+
+func TestCheckKeyStyle(t *testing.T) {
+	testCases := []struct {
+		name     string
+		tags     TagMap
+		keyStyle KeyStyle
+		expected []string
+	}{
+		{
+			name:     "no key style",
+			tags:     TagMap{"Owner": {}, "cost-center": {}},
+			keyStyle: KeyStyle{},
+			expected: nil,
+		},
+		{
+			name:     "PascalCase",
+			tags:     TagMap{"Owner": {}, "CostCenter": {}, "cost-center": {}, "costCenter": {}},
+			keyStyle: KeyStyle{Case: "PascalCase"},
+			expected: []string{"cost-center (not PascalCase)", "costCenter (not PascalCase)"},
+		},
+		{
+			name:     "camelCase",
+			tags:     TagMap{"owner": {}, "costCenter": {}, "CostCenter": {}},
+			keyStyle: KeyStyle{Case: "camelCase"},
+			expected: []string{"CostCenter (not camelCase)"},
+		},
+		{
+			name:     "kebab-case",
+			tags:     TagMap{"owner": {}, "cost-center": {}, "cost_center": {}, "Cost-Center": {}},
+			keyStyle: KeyStyle{Case: "kebab-case"},
+			expected: []string{"Cost-Center (not kebab-case)", "cost_center (not kebab-case)"},
+		},
+		{
+			name:     "snake_case",
+			tags:     TagMap{"owner": {}, "cost_center": {}, "cost-center": {}},
+			keyStyle: KeyStyle{Case: "snake_case"},
+			expected: []string{"cost-center (not snake_case)"},
+		},
+		{
+			name:     "allowed prefixes",
+			tags:     TagMap{"acme:Owner": {}, "team:Owner": {}, "other:Owner": {}, "Owner": {}},
+			keyStyle: KeyStyle{Prefixes: []string{"acme", "team"}},
+			expected: []string{"Owner (prefix not in [acme,team])", "other:Owner (prefix not in [acme,team])"},
+		},
+		{
+			name:     "allowed prefixes and case",
+			tags:     TagMap{"acme:Owner": {}, "acme:cost-center": {}},
+			keyStyle: KeyStyle{Case: "PascalCase", Prefixes: []string{"acme"}},
+			expected: []string{"acme:cost-center (not PascalCase)"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			actual := KeyIssues(checkKeyStyle(nil, tc.tags, tc.keyStyle))
+			if !reflect.DeepEqual(actual, tc.expected) {
+				t.Errorf("checkKeyStyle(%+v) = %#v; want %#v", tc.keyStyle, actual, tc.expected)
+			}
+		})
+	}
+}
+
+func TestFindingString(t *testing.T) {
+	testCases := []struct {
+		name     string
+		finding  Finding
+		expected string
+	}{
+		{
+			name:     "missing tag",
+			finding:  Finding{RuleID: RuleMissingTag, Tag: "Owner"},
+			expected: "Owner",
+		},
+		{
+			name:     "missing tag with values",
+			finding:  Finding{RuleID: RuleMissingTag, Tag: "Env", Expected: []string{"Dev", "Prod"}},
+			expected: "Env[Dev,Prod]",
+		},
+		{
+			name:     "invalid tag value",
+			finding:  Finding{RuleID: RuleInvalidTagValue, Tag: "Env", Expected: []string{"Prod"}, Actual: "Dev"},
+			expected: "Env[Prod]",
+		},
+		{
+			name:     "case duplicate",
+			finding:  Finding{RuleID: RuleDuplicateTagKey, Tag: "Owner/owner"},
+			expected: "Owner/owner (case duplicate)",
+		},
+		{
+			name:     "key style",
+			finding:  Finding{RuleID: RuleTagKeyStyle, Tag: "owner", Pattern: "PascalCase"},
+			expected: "owner (not PascalCase)",
+		},
+		{
+			name:     "key prefix",
+			finding:  Finding{RuleID: RuleTagKeyStyle, Tag: "team:Owner", Pattern: "[acme]:Name", Prefixes: []string{"acme"}},
+			expected: "team:Owner (prefix not in [acme])",
+		},
+		{
+			name:     "parse error",
+			finding:  Finding{RuleID: RuleParseError, Message: "Unclosed configuration block"},
+			expected: "Unclosed configuration block",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if actual := tc.finding.String(); actual != tc.expected {
+				t.Errorf("Finding.String() = %q; want %q", actual, tc.expected)
+			}
+		})
+	}
+}
+
+func TestParseErrorViolation(t *testing.T) {
+	testCases := []struct {
+		name     string
+		line     int
+		strict   bool
+		expected Violation
+	}{
+		{
+			name: "warning",
+			line: 3,
+			expected: Violation{Line: 3, FilePath: "main.tf", Findings: []Finding{
+				{RuleID: RuleParseError, Message: "bad", Severity: SeverityWarning},
+			}},
+		},
+		{
+			name:   "strict",
+			line:   3,
+			strict: true,
+			expected: Violation{Line: 3, FilePath: "main.tf", Findings: []Finding{
+				{RuleID: RuleParseError, Message: "bad", Severity: SeverityError},
+			}},
+		},
+		{
+			name: "unknown line",
+			expected: Violation{Line: 1, FilePath: "main.tf", Findings: []Finding{
+				{RuleID: RuleParseError, Message: "bad", Severity: SeverityWarning},
+			}},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			actual := NewParseError("main.tf", tc.line, "bad", tc.strict).Violation()
+			if !reflect.DeepEqual(actual, tc.expected) {
+				t.Errorf("NewParseError().Violation() = %+v; want %+v", actual, tc.expected)
+			}
+		})
+	}
+}
+
+// sortFindings sorts findings by rule and tag for stable comparison
+func sortFindings(findings []Finding) {
+	sort.Slice(findings, func(i, j int) bool {
+		if findings[i].RuleID != findings[j].RuleID {
+			return findings[i].RuleID < findings[j].RuleID
+		}
+		return findings[i].Tag < findings[j].Tag
+	})
+}
@@ -0,0 +1,51 @@
+package shared
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+)
+
+// TagFixStyle is how a missing tag is written into a resource
+type TagFixStyle string
+
+const (
+	FixHCLAttribute  TagFixStyle = "hcl-attribute"  // add a tags = { ... } attribute to the resource body
+	FixHCLObject     TagFixStyle = "hcl-object"     // add an entry to an existing tags = { ... } object
+	FixYAMLList      TagFixStyle = "yaml-list"      // add a - Key/Value item to an existing Tags list
+	FixYAMLAttribute TagFixStyle = "yaml-attribute" // add a Tags list to the resource Properties
+)
+
+// TagFix is where a missing tag can be added to a resource, for suggested fixes.
+// Scanners only set one when the resource layout is simple enough to edit safely.
+type TagFix struct {
+	Line   int    // the text is inserted at the start of this line
+	Indent string // of the inserted text
+	Style  TagFixStyle
+}
+
+var hclIdentifier = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_-]*$`)
+
+// Text is the source to insert to add a tag, ending in a newline
+func (f TagFix) Text(key string, value string) string {
+	switch f.Style {
+	case FixHCLAttribute:
+		return fmt.Sprintf("%stags = {\n%s  %s = %s\n%s}\n", f.Indent, f.Indent, hclKey(key), strconv.Quote(value), f.Indent)
+	case FixHCLObject:
+		return fmt.Sprintf("%s%s = %s\n", f.Indent, hclKey(key), strconv.Quote(value))
+	case FixYAMLList:
+		return fmt.Sprintf("%s- Key: %s\n%s  Value: %s\n", f.Indent, strconv.Quote(key), f.Indent, strconv.Quote(value))
+	case FixYAMLAttribute:
+		return fmt.Sprintf("%sTags:\n%s  - Key: %s\n%s    Value: %s\n", f.Indent, f.Indent, strconv.Quote(key), f.Indent, strconv.Quote(value))
+	default:
+		return ""
+	}
+}
+
+// hclKey quotes a tag key that is not a valid HCL identifier, eg "acme:Owner"
+func hclKey(key string) string {
+	if hclIdentifier.MatchString(key) {
+		return key
+	}
+	return strconv.Quote(key)
+}
@@ -0,0 +1,31 @@
+package shared
+
+import "testing"
+
+func TestMatchGlob(t *testing.T) {
+	testCases := []struct {
+		name     string
+		pattern  string
+		path     string
+		expected bool
+	}{
+		{name: "exact", pattern: "template.yaml", path: "template.yaml", expected: true},
+		{name: "wildcard", pattern: "*.yaml", path: "template.yaml", expected: true},
+		{name: "wildcard does not cross directories", pattern: "*.yaml", path: "templates/template.yaml", expected: false},
+		{name: "double star", pattern: "templates/**/*.yaml", path: "templates/network/vpc.yaml", expected: true},
+		{name: "double star matches no directories", pattern: "templates/**/*.yaml", path: "templates/vpc.yaml", expected: true},
+		{name: "double star matches many directories", pattern: "templates/**/*.yaml", path: "templates/a/b/c/vpc.yaml", expected: true},
+		{name: "leading double star", pattern: "**/*.template.json", path: "infra/app.template.json", expected: true},
+		{name: "wrong directory", pattern: "templates/**/*.yaml", path: "charts/templates/vpc.yaml", expected: false},
+		{name: "wrong extension", pattern: "templates/**/*.yaml", path: "templates/vpc.yml", expected: false},
+		{name: "trailing double star", pattern: "templates/**", path: "templates/a/vpc.yml", expected: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if actual := MatchGlob(tc.pattern, tc.path); actual != tc.expected {
+				t.Errorf("MatchGlob(%q, %q) = %v; want %v", tc.pattern, tc.path, actual, tc.expected)
+			}
+		})
+	}
+}
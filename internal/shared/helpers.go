@@ -1,38 +1,14 @@
 package shared
 
 import (
-	"fmt"
+	"slices"
 	"sort"
 	"strings"
 )
 
 // FilterMissingTags checks effectiveTags against requiredTags
 func FilterMissingTags(requiredTags TagMap, effectiveTags TagMap, caseInsensitive bool) []string {
-	var missingTags []string
-
-	for requiredKey, allowedValues := range requiredTags {
-		effectiveValues, keyFound := matchTagKey(requiredKey, effectiveTags, caseInsensitive)
-
-		// construct violation message
-		violationMessage := requiredKey
-		if len(allowedValues) > 0 {
-			violationMessage = fmt.Sprintf("%s[%s]", requiredKey, strings.Join(allowedValues, ","))
-		}
-		if !keyFound {
-			missingTags = append(missingTags, violationMessage)
-			continue
-		}
-
-		// if there are tag values required, check them
-		if len(allowedValues) > 0 {
-			if !matchTagValue(allowedValues, effectiveValues, caseInsensitive) {
-				missingTags = append(missingTags, violationMessage)
-			}
-		}
-	}
-
-	sort.Strings(missingTags) //
-	return missingTags
+	return MissingTags(checkRequiredTags(Rules{RequiredTags: requiredTags}, effectiveTags, caseInsensitive))
 }
 
 // matchTagKey checks required tag key against effective tags
@@ -79,3 +55,101 @@ func CompareCase(first, second string, caseInsensitive bool) bool {
 	}
 	return first == second
 }
+
+// NormalizeTags lowers the case of tag keys if caseInsensitive is true
+func NormalizeTags(tags TagMap, caseInsensitive bool) TagMap {
+	normalized := make(TagMap)
+	for key, values := range tags {
+		normalized[NormalizeCase(key, caseInsensitive)] = values
+	}
+	return normalized
+}
+
+// TagKeys returns the distinct keys of one or more tag maps
+func TagKeys(tagMaps ...TagMap) []string {
+	var keys []string
+	for _, tags := range tagMaps {
+		for key := range tags {
+			if !slices.Contains(keys, key) {
+				keys = append(keys, key)
+			}
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// ParseKeyCase returns the supported key case matching name, eg "pascalcase" returns "PascalCase"
+func ParseKeyCase(name string) (string, bool) {
+	for keyCase := range keyCasePatterns {
+		if strings.EqualFold(keyCase, name) {
+			return keyCase, true
+		}
+	}
+	return "", false
+}
+
+// MergeTags combines multiple tag maps, later maps take precedence
+func MergeTags(tagMaps ...TagMap) TagMap {
+	merged := make(TagMap)
+	for _, m := range tagMaps {
+		for k, v := range m {
+			merged[k] = v
+		}
+	}
+	return merged
+}
+
+// severityRanks orders severities, higher is more severe
+var severityRanks = map[Severity]int{
+	SeverityInfo:    1,
+	SeverityWarning: 2,
+	SeverityError:   3,
+}
+
+// ParseSeverity returns the severity matching name, eg "warning"
+func ParseSeverity(name string) (Severity, bool) {
+	severity := Severity(strings.ToLower(strings.TrimSpace(name)))
+	_, ok := severityRanks[severity]
+	return severity, ok
+}
+
+// AtLeast reports whether the severity meets the threshold, an empty severity is treated as error
+func (s Severity) AtLeast(threshold Severity) bool {
+	return s.rank() >= threshold.rank()
+}
+
+func (s Severity) rank() int {
+	if rank, ok := severityRanks[s]; ok {
+		return rank
+	}
+	return severityRanks[SeverityError]
+}
+
+// Severity returns the highest severity of the violation's unsuppressed findings
+func (v Violation) Severity() Severity {
+	highest, found := SeverityInfo, false
+	for _, f := range v.Findings {
+		if f.Suppressed {
+			continue
+		}
+		found = true
+		if f.Severity.rank() > highest.rank() {
+			highest = f.Severity
+		}
+	}
+	if !found {
+		return SeverityError
+	}
+	return highest
+}
+
+// Compliant reports whether a violation leaves its resource compliant, with no unsuppressed findings at or above failOn
+func (v Violation) Compliant(failOn Severity) bool {
+	for _, f := range v.Findings {
+		if !f.Suppressed && f.Severity.AtLeast(failOn) {
+			return false
+		}
+	}
+	return true
+}
@@ -307,3 +307,209 @@ func TestCompareCase(t *testing.T) {
 		})
 	}
 }
+
+func TestMergeTags(t *testing.T) {
+	testCases := []struct {
+		name     string
+		inputs   []TagMap
+		expected TagMap
+	}{
+		{
+			name:     "empty",
+			inputs:   []TagMap{},
+			expected: TagMap{},
+		},
+		{
+			name: "key",
+			inputs: []TagMap{
+				{"Environment": {}},
+			},
+			expected: TagMap{"Environment": {}},
+		},
+		{
+			name:     "key and value",
+			inputs:   []TagMap{{"Environment": {"Dev"}}},
+			expected: TagMap{"Environment": {"Dev"}},
+		},
+		{
+			name: "multiple keys and values",
+			inputs: []TagMap{
+				{"Environment": {"Dev"}},
+				{"Owner": {"Prod"}},
+			},
+			expected: TagMap{"Environment": {"Dev"}, "Owner": {"Prod"}},
+		},
+		{
+			name: "overlapping values, last wins",
+			inputs: []TagMap{
+				{"Environment": {"Dev"}, "Owner": {"jakebark"}},
+				{"Owner": {"Jake"}, "CostCenter": {"C-01"}},
+			},
+			expected: TagMap{"Environment": {"Dev"}, "Owner": {"Jake"}, "CostCenter": {"C-01"}},
+		},
+		{
+			name: "overlapping empty value, last wins",
+			inputs: []TagMap{
+				{"Environment": {"Dev"}},
+				{"Environment": {}},
+			},
+			expected: TagMap{"Environment": {}},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			actual := MergeTags(tc.inputs...)
+			if !reflect.DeepEqual(actual, tc.expected) {
+				t.Errorf("MergeTags() = %v; want %v", actual, tc.expected)
+			}
+		})
+	}
+}
+
+func TestParseKeyCase(t *testing.T) {
+	testCases := []struct {
+		name       string
+		input      string
+		expected   string
+		expectedOk bool
+	}{
+		{name: "exact", input: "PascalCase", expected: "PascalCase", expectedOk: true},
+		{name: "lower case", input: "kebab-case", expected: "kebab-case", expectedOk: true},
+		{name: "case insensitive", input: "SNAKE_CASE", expected: "snake_case", expectedOk: true},
+		{name: "unsupported", input: "Title Case", expected: "", expectedOk: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			actual, ok := ParseKeyCase(tc.input)
+			if actual != tc.expected || ok != tc.expectedOk {
+				t.Errorf("ParseKeyCase(%q) = %q, %v; want %q, %v", tc.input, actual, ok, tc.expected, tc.expectedOk)
+			}
+		})
+	}
+}
+
+func TestParseSeverity(t *testing.T) {
+	testCases := []struct {
+		name       string
+		input      string
+		expected   Severity
+		expectedOk bool
+	}{
+		{name: "error", input: "error", expected: SeverityError, expectedOk: true},
+		{name: "warning", input: "warning", expected: SeverityWarning, expectedOk: true},
+		{name: "info, upper case", input: "INFO", expected: SeverityInfo, expectedOk: true},
+		{name: "unsupported", input: "critical", expectedOk: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			actual, ok := ParseSeverity(tc.input)
+			if ok != tc.expectedOk || (ok && actual != tc.expected) {
+				t.Errorf("ParseSeverity(%q) = %q, %v; want %q, %v", tc.input, actual, ok, tc.expected, tc.expectedOk)
+			}
+		})
+	}
+}
+
+func TestSeverityAtLeast(t *testing.T) {
+	testCases := []struct {
+		name      string
+		severity  Severity
+		threshold Severity
+		expected  bool
+	}{
+		{name: "error meets error", severity: SeverityError, threshold: SeverityError, expected: true},
+		{name: "warning below error", severity: SeverityWarning, threshold: SeverityError, expected: false},
+		{name: "warning meets warning", severity: SeverityWarning, threshold: SeverityWarning, expected: true},
+		{name: "error meets info", severity: SeverityError, threshold: SeverityInfo, expected: true},
+		{name: "info below warning", severity: SeverityInfo, threshold: SeverityWarning, expected: false},
+		{name: "empty treated as error", severity: "", threshold: SeverityError, expected: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if actual := tc.severity.AtLeast(tc.threshold); actual != tc.expected {
+				t.Errorf("%q.AtLeast(%q) = %v; want %v", tc.severity, tc.threshold, actual, tc.expected)
+			}
+		})
+	}
+}
+
+func TestViolationSeverity(t *testing.T) {
+	testCases := []struct {
+		name      string
+		violation Violation
+		expected  Severity
+	}{
+		{
+			name:      "no findings",
+			violation: Violation{},
+			expected:  SeverityError,
+		},
+		{
+			name: "warnings only",
+			violation: Violation{Findings: []Finding{
+				{Tag: "a", Severity: SeverityInfo},
+				{Tag: "b", Severity: SeverityWarning},
+			}},
+			expected: SeverityWarning,
+		},
+		{
+			name: "highest severity",
+			violation: Violation{Findings: []Finding{
+				{Tag: "a", Severity: SeverityWarning},
+				{Tag: "b", Severity: SeverityError},
+			}},
+			expected: SeverityError,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if actual := tc.violation.Severity(); actual != tc.expected {
+				t.Errorf("Violation.Severity() = %q; want %q", actual, tc.expected)
+			}
+		})
+	}
+}
+
+func TestViolationCompliant(t *testing.T) {
+	testCases := []struct {
+		name      string
+		violation Violation
+		failOn    Severity
+		expected  bool
+	}{
+		{
+			name:      "error",
+			violation: Violation{Findings: []Finding{{Tag: "a", Severity: SeverityError}}},
+			expected:  false,
+		},
+		{
+			name:      "ignored",
+			violation: Violation{Findings: []Finding{{Tag: "a", Severity: SeverityError, Suppressed: true}}},
+			expected:  true,
+		},
+		{
+			name:      "below fail on",
+			violation: Violation{Findings: []Finding{{Tag: "a", Severity: SeverityInfo}, {Tag: "b", Severity: SeverityWarning}}},
+			expected:  true,
+		},
+		{
+			name:      "at fail on",
+			violation: Violation{Findings: []Finding{{Tag: "a", Severity: SeverityInfo}, {Tag: "b", Severity: SeverityWarning}}},
+			failOn:    SeverityWarning,
+			expected:  false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if actual := tc.violation.Compliant(tc.failOn); actual != tc.expected {
+				t.Errorf("Violation.Compliant(%q) = %v; want %v", tc.failOn, actual, tc.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,241 @@
+package shared
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jakebark/tag-nag/internal/config"
+)
+
+// Ignore is a parsed ignore comment, eg #tag-nag ignore Owner,CostCenter reason="shared infra" until=2026-12-31
+type Ignore struct {
+	All    bool      // ignore-all, applies to the whole file
+	Tags   []string  // empty ignores every tag
+	Reason string    // optional justification
+	Until  time.Time // zero never expires
+	Line   int       // where the comment starts, set by the scanner
+}
+
+// Comment is a comment in a source file, and the line it starts on
+type Comment struct {
+	Text string
+	Line int
+}
+
+// ParseIgnore parses an ignore comment, eg "#tag-nag ignore" or "// tag-nag ignore Owner"
+// multi-line comments are checked line by line
+func ParseIgnore(comment string) (Ignore, bool, error) {
+	marker := strings.TrimPrefix(config.TagNagIgnore, "#")
+	for _, line := range strings.Split(comment, "\n") {
+		text := strings.TrimSpace(line)
+		for _, delimiter := range []string{"#", "//", "/*", "*"} {
+			if strings.HasPrefix(text, delimiter) {
+				text = strings.TrimPrefix(text, delimiter)
+				break
+			}
+		}
+		text = strings.TrimSpace(strings.TrimSuffix(text, "*/"))
+		if !strings.HasPrefix(text, marker) {
+			continue
+		}
+		if ignore, found, err := parseIgnoreOptions(strings.TrimPrefix(text, marker)); found {
+			return ignore, found, err
+		}
+	}
+	return Ignore{}, false, nil
+}
+
+// parseIgnoreOptions parses the text following the ignore marker, eg "-all" or " Owner reason=\"...\""
+func parseIgnoreOptions(rest string) (Ignore, bool, error) {
+	var ignore Ignore
+	if strings.HasPrefix(rest, "-all") {
+		ignore.All = true
+		rest = strings.TrimPrefix(rest, "-all")
+	}
+	if rest != "" && !strings.HasPrefix(rest, " ") && !strings.HasPrefix(rest, "\t") {
+		return ignore, false, nil // eg "#tag-nag ignored"
+	}
+
+	for _, token := range splitIgnoreOptions(rest) {
+		key, value, hasValue := strings.Cut(token, "=")
+		if !hasValue {
+			for _, tag := range strings.Split(token, ",") {
+				if trimmed := strings.TrimSpace(tag); trimmed != "" {
+					ignore.Tags = append(ignore.Tags, trimmed)
+				}
+			}
+			continue
+		}
+
+		value = strings.Trim(value, `"`)
+		switch key {
+		case "reason":
+			ignore.Reason = value
+		case "until":
+			until, err := time.Parse("2006-01-02", value)
+			if err != nil {
+				return ignore, true, fmt.Errorf("invalid until date '%s', expected YYYY-MM-DD", value)
+			}
+			ignore.Until = until
+		default:
+			return ignore, true, fmt.Errorf("unknown ignore option '%s'", key)
+		}
+	}
+
+	return ignore, true, nil
+}
+
+// splitIgnoreOptions splits on whitespace outside of double quotes
+func splitIgnoreOptions(input string) []string {
+	var tokens []string
+	var current strings.Builder
+	inQuotes := false
+
+	for _, r := range input {
+		switch {
+		case r == '"':
+			inQuotes = !inQuotes
+			current.WriteRune(r)
+		case (r == ' ' || r == '\t') && !inQuotes:
+			if current.Len() > 0 {
+				tokens = append(tokens, current.String())
+				current.Reset()
+			}
+		default:
+			current.WriteRune(r)
+		}
+	}
+	if current.Len() > 0 {
+		tokens = append(tokens, current.String())
+	}
+	return tokens
+}
+
+// Expired reports whether the ignore has passed its until date, the until date itself is still honoured
+func (i Ignore) Expired(now time.Time) bool {
+	return !i.Until.IsZero() && !now.Before(i.Until.AddDate(0, 0, 1))
+}
+
+// covers reports whether the ignore applies to a finding
+func (i Ignore) covers(f Finding) bool {
+	if len(i.Tags) == 0 {
+		return true
+	}
+	for _, tag := range i.Tags {
+		for _, findingTag := range strings.Split(f.Tag, "/") { // case duplicates, eg "Owner/owner"
+			if strings.EqualFold(tag, findingTag) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// ApplyIgnore suppresses the findings covered by an ignore comment, skipping the violation if none remain
+// expired ignores, and ignores without a reason when one is required, are recorded as findings instead
+// it reports whether the ignore had any findings to act on
+func ApplyIgnore(violation *Violation, ignore Ignore, requireReason bool, now time.Time) bool {
+	if len(violation.Findings) == 0 {
+		return false
+	}
+	if requireReason && ignore.Reason == "" {
+		violation.Findings = append(violation.Findings, Finding{
+			RuleID:   RuleIgnoreWithoutReason,
+			Severity: SeverityInfo,
+		})
+		return true
+	}
+	if ignore.Expired(now) {
+		violation.Findings = append(violation.Findings, Finding{
+			RuleID:   RuleExpiredIgnore,
+			Actual:   ignore.Until.Format("2006-01-02"),
+			Severity: SeverityInfo,
+		})
+		return true
+	}
+
+	suppressed, remaining := 0, 0
+	for i := range violation.Findings {
+		if ignore.covers(violation.Findings[i]) {
+			violation.Findings[i].Suppressed = true
+			violation.Findings[i].Reason = ignore.Reason
+			suppressed++
+		} else if !violation.Findings[i].Suppressed {
+			remaining++
+		}
+	}
+
+	if remaining == 0 {
+		violation.Skip = true
+		violation.SkipReason = ignore.Reason
+	}
+	return suppressed > 0
+}
+
+// ApplyIgnores applies a resource's ignore comment, or failing that the file's ignore-all, to its violation
+// an unused resource ignore is flagged when rules.ReportUnusedIgnores is set
+// it reports whether the file's ignore-all was used
+func ApplyIgnores(violation *Violation, resourceIgnore *Ignore, fileIgnore *Ignore, rules Rules, now time.Time) bool {
+	if resourceIgnore != nil {
+		if !ApplyIgnore(violation, *resourceIgnore, rules.RequireIgnoreReason, now) && rules.ReportUnusedIgnores {
+			violation.Findings = append(violation.Findings, Finding{
+				RuleID:   RuleUnusedIgnore,
+				Severity: SeverityError,
+			})
+		}
+		return false
+	}
+	if fileIgnore != nil {
+		return ApplyIgnore(violation, *fileIgnore, rules.RequireIgnoreReason, now)
+	}
+	return false
+}
+
+// FindIgnoreAll returns the file-level ignore-all comment, if present
+func FindIgnoreAll(comments []Comment) (*Ignore, error) {
+	for _, comment := range comments {
+		ignore, found, err := ParseIgnore(comment.Text)
+		if found && ignore.All {
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", comment.Line, err)
+			}
+			ignore.Line = comment.Line
+			return &ignore, nil
+		}
+	}
+	return nil, nil
+}
+
+// UnusedIgnoreViolations reports an ignore-all that suppressed nothing, ignore comments on resources that are not checked,
+// eg a resource that is not taggable, so can suppress nothing, and ignore comments not attached to a resource
+func UnusedIgnoreViolations(comments []Comment, attached []Ignore, unchecked []Ignore, fileIgnore *Ignore, fileIgnoreUsed bool, filePath string) []Violation {
+	var violations []Violation
+	if fileIgnore != nil && !fileIgnoreUsed {
+		violations = append(violations, ignoreViolation(RuleUnusedIgnore, fileIgnore.Line, filePath))
+	}
+	for _, ignore := range unchecked {
+		violations = append(violations, ignoreViolation(RuleUnusedIgnore, ignore.Line, filePath))
+	}
+
+	attachedLines := make(map[int]bool)
+	for _, ignore := range attached {
+		attachedLines[ignore.Line] = true
+	}
+	for _, comment := range comments {
+		ignore, found, _ := ParseIgnore(comment.Text)
+		if found && !ignore.All && !attachedLines[comment.Line] {
+			violations = append(violations, ignoreViolation(RuleUnattachedIgnore, comment.Line, filePath))
+		}
+	}
+	return violations
+}
+
+// ignoreViolation is a file-level violation for an ignore comment
+func ignoreViolation(ruleID string, line int, filePath string) Violation {
+	return Violation{
+		Line:     line,
+		Findings: []Finding{{RuleID: ruleID, Severity: SeverityError}},
+		FilePath: filePath,
+	}
+}
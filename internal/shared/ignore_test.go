@@ -0,0 +1,285 @@
+package shared
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParseIgnore(t *testing.T) {
+	testCases := []struct {
+		name          string
+		comment       string
+		expected      Ignore
+		expectedFound bool
+		expectError   bool
+	}{
+		{
+			name:     "no comment",
+			comment:  `  bucket = "test"`,
+			expected: Ignore{},
+		},
+		{
+			name:          "bare ignore",
+			comment:       "  #tag-nag ignore",
+			expected:      Ignore{},
+			expectedFound: true,
+		},
+		{
+			name:          "ignore all",
+			comment:       "#tag-nag ignore-all",
+			expected:      Ignore{All: true},
+			expectedFound: true,
+		},
+		{
+			name:          "tags, reason and until",
+			comment:       `  #tag-nag ignore Owner,CostCenter reason="shared infra" until=2026-12-31`,
+			expected:      Ignore{Tags: []string{"Owner", "CostCenter"}, Reason: "shared infra", Until: time.Date(2026, 12, 31, 0, 0, 0, 0, time.UTC)},
+			expectedFound: true,
+		},
+		{
+			name:          "reason only, spaced",
+			comment:       `# tag-nag ignore reason="legacy"`,
+			expected:      Ignore{Reason: "legacy"},
+			expectedFound: true,
+		},
+		{
+			name:          "double slash comment",
+			comment:       "// tag-nag ignore Owner\n",
+			expected:      Ignore{Tags: []string{"Owner"}},
+			expectedFound: true,
+		},
+		{
+			name:          "block comment",
+			comment:       "/* tag-nag ignore Owner */",
+			expected:      Ignore{Tags: []string{"Owner"}},
+			expectedFound: true,
+		},
+		{
+			name:          "multi-line comment",
+			comment:       "# owned by platform\n\n#tag-nag ignore",
+			expected:      Ignore{},
+			expectedFound: true,
+		},
+		{
+			name:     "marker not at start of comment",
+			comment:  `bucket = "#tag-nag ignore"`,
+			expected: Ignore{},
+		},
+		{
+			name:     "different word",
+			comment:  "#tag-nag ignored",
+			expected: Ignore{},
+		},
+		{
+			name:          "invalid until",
+			comment:       "#tag-nag ignore until=31/12/2026",
+			expectedFound: true,
+			expectError:   true,
+		},
+		{
+			name:          "unknown option",
+			comment:       "#tag-nag ignore expires=2026-12-31",
+			expectedFound: true,
+			expectError:   true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ignore, found, err := ParseIgnore(tc.comment)
+			if found != tc.expectedFound {
+				t.Errorf("ParseIgnore(%q) found = %v, want %v", tc.comment, found, tc.expectedFound)
+			}
+			if (err != nil) != tc.expectError {
+				t.Fatalf("ParseIgnore(%q) error = %v, expectError %v", tc.comment, err, tc.expectError)
+			}
+			if !tc.expectError && !reflect.DeepEqual(ignore, tc.expected) {
+				t.Errorf("ParseIgnore(%q) = %+v, want %+v", tc.comment, ignore, tc.expected)
+			}
+		})
+	}
+}
+
+func TestApplyIgnore(t *testing.T) {
+	now := time.Date(2026, 6, 1, 12, 0, 0, 0, time.UTC)
+	owner := Finding{RuleID: RuleMissingTag, Tag: "Owner", Severity: SeverityError}
+	env := Finding{RuleID: RuleMissingTag, Tag: "Env", Severity: SeverityError}
+
+	testCases := []struct {
+		name             string
+		ignore           Ignore
+		requireReason    bool
+		expectedFindings []Finding
+		expectedSkip     bool
+	}{
+		{
+			name:   "bare ignore skips",
+			ignore: Ignore{},
+			expectedFindings: []Finding{
+				{RuleID: RuleMissingTag, Tag: "Owner", Severity: SeverityError, Suppressed: true},
+				{RuleID: RuleMissingTag, Tag: "Env", Severity: SeverityError, Suppressed: true},
+			},
+			expectedSkip: true,
+		},
+		{
+			name:   "listed tags only",
+			ignore: Ignore{Tags: []string{"owner"}, Reason: "shared"},
+			expectedFindings: []Finding{
+				{RuleID: RuleMissingTag, Tag: "Owner", Severity: SeverityError, Suppressed: true, Reason: "shared"},
+				env,
+			},
+		},
+		{
+			name:   "until date still honoured",
+			ignore: Ignore{Until: time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)},
+			expectedFindings: []Finding{
+				{RuleID: RuleMissingTag, Tag: "Owner", Severity: SeverityError, Suppressed: true},
+				{RuleID: RuleMissingTag, Tag: "Env", Severity: SeverityError, Suppressed: true},
+			},
+			expectedSkip: true,
+		},
+		{
+			name:   "expired",
+			ignore: Ignore{Until: time.Date(2026, 5, 31, 0, 0, 0, 0, time.UTC)},
+			expectedFindings: []Finding{
+				owner,
+				env,
+				{RuleID: RuleExpiredIgnore, Actual: "2026-05-31", Severity: SeverityInfo},
+			},
+		},
+		{
+			name:          "reason required",
+			ignore:        Ignore{},
+			requireReason: true,
+			expectedFindings: []Finding{
+				owner,
+				env,
+				{RuleID: RuleIgnoreWithoutReason, Severity: SeverityInfo},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			violation := Violation{Findings: []Finding{owner, env}}
+			ApplyIgnore(&violation, tc.ignore, tc.requireReason, now)
+
+			if !reflect.DeepEqual(violation.Findings, tc.expectedFindings) {
+				t.Errorf("ApplyIgnore() findings = %+v, want %+v", violation.Findings, tc.expectedFindings)
+			}
+			if violation.Skip != tc.expectedSkip {
+				t.Errorf("ApplyIgnore() skip = %v, want %v", violation.Skip, tc.expectedSkip)
+			}
+			if violation.Skip && violation.SkipReason != tc.ignore.Reason {
+				t.Errorf("ApplyIgnore() skip reason = %q, want %q", violation.SkipReason, tc.ignore.Reason)
+			}
+		})
+	}
+}
+
+func TestApplyIgnores(t *testing.T) {
+	now := time.Date(2026, 6, 1, 12, 0, 0, 0, time.UTC)
+	owner := Finding{RuleID: RuleMissingTag, Tag: "Owner", Severity: SeverityError}
+	unused := Finding{RuleID: RuleUnusedIgnore, Severity: SeverityError}
+
+	testCases := []struct {
+		name             string
+		findings         []Finding
+		resourceIgnore   *Ignore
+		fileIgnore       *Ignore
+		reportUnused     bool
+		expectedFindings []Finding
+		expectedFileUsed bool
+	}{
+		{
+			name:             "no ignores",
+			findings:         []Finding{owner},
+			expectedFindings: []Finding{owner},
+		},
+		{
+			name:             "file ignore used",
+			findings:         []Finding{owner},
+			fileIgnore:       &Ignore{All: true},
+			expectedFindings: []Finding{{RuleID: RuleMissingTag, Tag: "Owner", Severity: SeverityError, Suppressed: true}},
+			expectedFileUsed: true,
+		},
+		{
+			name:             "resource ignore takes precedence",
+			findings:         []Finding{owner},
+			resourceIgnore:   &Ignore{Reason: "legacy"},
+			fileIgnore:       &Ignore{All: true},
+			expectedFindings: []Finding{{RuleID: RuleMissingTag, Tag: "Owner", Severity: SeverityError, Suppressed: true, Reason: "legacy"}},
+		},
+		{
+			name:           "unused resource ignore, not reported",
+			resourceIgnore: &Ignore{},
+		},
+		{
+			name:             "unused resource ignore",
+			resourceIgnore:   &Ignore{},
+			reportUnused:     true,
+			expectedFindings: []Finding{unused},
+		},
+		{
+			name:             "resource ignore lists other tags",
+			findings:         []Finding{owner},
+			resourceIgnore:   &Ignore{Tags: []string{"CostCenter"}},
+			reportUnused:     true,
+			expectedFindings: []Finding{owner, unused},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			violation := Violation{Findings: append([]Finding(nil), tc.findings...)}
+			fileUsed := ApplyIgnores(&violation, tc.resourceIgnore, tc.fileIgnore, Rules{ReportUnusedIgnores: tc.reportUnused}, now)
+
+			if !reflect.DeepEqual(violation.Findings, tc.expectedFindings) {
+				t.Errorf("ApplyIgnores() findings = %+v, want %+v", violation.Findings, tc.expectedFindings)
+			}
+			if fileUsed != tc.expectedFileUsed {
+				t.Errorf("ApplyIgnores() file ignore used = %v, want %v", fileUsed, tc.expectedFileUsed)
+			}
+		})
+	}
+}
+
+func TestUnusedIgnoreViolations(t *testing.T) {
+	comments := []Comment{
+		{Text: "#tag-nag ignore-all\n", Line: 1},
+		{Text: "# owned by platform\n", Line: 3},
+		{Text: "#tag-nag ignore\n", Line: 4},
+		{Text: "#tag-nag ignore Owner\n", Line: 9},
+	}
+	attached := []Ignore{{Line: 4}}
+	fileIgnore := &Ignore{All: true, Line: 1}
+
+	t.Run("file ignore used", func(t *testing.T) {
+		violations := UnusedIgnoreViolations(comments, attached, nil, fileIgnore, true, "main.tf")
+		expected := []Violation{
+			{Line: 9, Findings: []Finding{{RuleID: RuleUnattachedIgnore, Severity: SeverityError}}, FilePath: "main.tf"},
+		}
+		if !reflect.DeepEqual(violations, expected) {
+			t.Errorf("UnusedIgnoreViolations() = %+v, want %+v", violations, expected)
+		}
+	})
+
+	t.Run("file ignore unused", func(t *testing.T) {
+		violations := UnusedIgnoreViolations(comments, attached, nil, fileIgnore, false, "main.tf")
+		if len(violations) != 2 || violations[0].Line != 1 || violations[0].Findings[0].RuleID != RuleUnusedIgnore {
+			t.Errorf("UnusedIgnoreViolations() = %+v, want an unused ignore-all on line 1 and an unattached ignore", violations)
+		}
+	})
+
+	t.Run("ignore on a resource that is not checked", func(t *testing.T) {
+		violations := UnusedIgnoreViolations(comments, attached, []Ignore{{Line: 4}}, fileIgnore, true, "main.tf")
+		expected := []Violation{
+			{Line: 4, Findings: []Finding{{RuleID: RuleUnusedIgnore, Severity: SeverityError}}, FilePath: "main.tf"},
+			{Line: 9, Findings: []Finding{{RuleID: RuleUnattachedIgnore, Severity: SeverityError}}, FilePath: "main.tf"},
+		}
+		if !reflect.DeepEqual(violations, expected) {
+			t.Errorf("UnusedIgnoreViolations() = %+v, want %+v", violations, expected)
+		}
+	})
+}
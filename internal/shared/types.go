@@ -3,19 +3,164 @@ package shared
 type TagMap map[string][]string
 
 type Violation struct {
-	ResourceType string   `json:"resource_type"`
-	ResourceName string   `json:"resource_name"`
-	Line         int      `json:"line"`
-	MissingTags  []string `json:"missing_tags"`
-	Skip         bool     `json:"skip"`
-	FilePath     string   `json:"file_path"`
+	ResourceType string    `json:"resource_type"`
+	ResourceName string    `json:"resource_name"`
+	Construct    string    `json:"construct,omitempty"` // cdk construct path, from the template metadata
+	Line         int       `json:"line"`
+	EndLine      int       `json:"end_line,omitempty"`   // last line of the resource block
+	MissingTags  []string  `json:"missing_tags"`         // kept for backward compatibility, see Findings
+	KeyIssues    []string  `json:"key_issues,omitempty"` // kept for backward compatibility, see Findings
+	Findings     []Finding `json:"findings"`
+	Skip         bool      `json:"skip"`
+	SkipReason   string    `json:"skip_reason,omitempty"`
+	FilePath     string    `json:"file_path"`
+	Fix          *TagFix   `json:"-"` // where missing tags can be added, nil when not known
+}
+
+// Resource is a taggable resource that was checked, whether or not it has violations
+type Resource struct {
+	ResourceType string `json:"resource_type"`
+	ResourceName string `json:"resource_name"`
+	Line         int    `json:"line"`
+	FilePath     string `json:"file_path"`
+	Tags         TagMap `json:"tags"` // effective tags, including inherited default tags
+	Compliant    bool   `json:"compliant"`
+}
+
+// Finding is a single failed tag rule on a resource
+type Finding struct {
+	RuleID          string   `json:"rule_id"`
+	Tag             string   `json:"tag"`
+	Expected        []string `json:"expected,omitempty"` // allowed values
+	Pattern         string   `json:"pattern,omitempty"`  // required key style
+	Prefixes        []string `json:"prefixes,omitempty"` // allowed key prefixes, when the key's prefix is not one of them
+	Actual          string   `json:"actual,omitempty"`   // value found on the resource
+	FromDefaultTags bool     `json:"from_default_tags"`
+	InheritedFrom   string   `json:"inherited_from,omitempty"` // where an inherited tag was set, eg default_tags or stack tags
+	Severity        Severity `json:"severity"`
+	Suppressed      bool     `json:"suppressed,omitempty"` // by an ignore comment
+	Reason          string   `json:"reason,omitempty"`     // from the ignore comment
+	Message         string   `json:"message,omitempty"`    // diagnostic, for parse errors
+	Line            int      `json:"line,omitempty"`       // where the finding is, eg a tag value, when it is not the resource's line
+}
+
+type Severity string
+
+const (
+	SeverityError   Severity = "error"
+	SeverityWarning Severity = "warning"
+	SeverityInfo    Severity = "info"
+)
+
+// Rules are the tag rules each resource is checked against
+type Rules struct {
+	RequiredTags TagMap
+	Severities   map[string]Severity // by required tag key, defaults to error
+	KeyStyle     KeyStyle
+	FailOn       Severity // findings at or above it make a resource non-compliant, error when empty
+
+	RequireIgnoreReason bool // ignore comments without a reason are not honoured
+	ReportUnusedIgnores bool // ignore comments that suppress nothing are reported
+	Strict              bool // files that cannot be parsed are errors, rather than warnings
+}
+
+// rule IDs
+const (
+	RuleMissingTag      = "missing-tag"
+	RuleInvalidTagValue = "invalid-tag-value"
+	RuleDuplicateTagKey = "duplicate-tag-key"
+	RuleTagKeyStyle     = "tag-key-style"
+
+	RuleIgnoreWithoutReason = "ignore-without-reason"
+	RuleExpiredIgnore       = "expired-ignore"
+	RuleUnusedIgnore        = "unused-ignore"
+	RuleUnattachedIgnore    = "unattached-ignore"
+
+	RuleParseError = "parse-error"
+)
+
+// KeyStyle is the naming convention that tag keys must follow
+type KeyStyle struct {
+	Case     string   // PascalCase, camelCase, kebab-case or snake_case
+	Prefixes []string // if set, keys must be "prefix:Name" with an allowed prefix
+	Severity Severity // defaults to error
 }
 
 type OutputFormat string
 
 const (
-	OutputFormatText     OutputFormat = "text"
-	OutputFormatJSON     OutputFormat = "json"
-	OutputFormatJUnitXML OutputFormat = "junit-xml"
-	OutputFormatSARIF    OutputFormat = "sarif"
+	OutputFormatText       OutputFormat = "text"
+	OutputFormatJSON       OutputFormat = "json"
+	OutputFormatJUnitXML   OutputFormat = "junit-xml"
+	OutputFormatSARIF      OutputFormat = "sarif"
+	OutputFormatMarkdown   OutputFormat = "markdown"
+	OutputFormatGitHub     OutputFormat = "github"
+	OutputFormatGitLab     OutputFormat = "gitlab-codequality"
+	OutputFormatCheckstyle OutputFormat = "checkstyle"
+	OutputFormatCSV        OutputFormat = "csv"
+	OutputFormatTSV        OutputFormat = "tsv"
+	OutputFormatHTML       OutputFormat = "html"
+	OutputFormatTemplate   OutputFormat = "template"
+)
+
+// OutputFormats are the supported output formats, in the order they are documented
+var OutputFormats = []OutputFormat{
+	OutputFormatText,
+	OutputFormatJSON,
+	OutputFormatJUnitXML,
+	OutputFormatSARIF,
+	OutputFormatMarkdown,
+	OutputFormatGitHub,
+	OutputFormatGitLab,
+	OutputFormatCheckstyle,
+	OutputFormatCSV,
+	OutputFormatTSV,
+	OutputFormatHTML,
+	OutputFormatTemplate,
+}
+
+// Output is a report to write, eg json to report.json
+type Output struct {
+	Format OutputFormat `yaml:"format"`
+	File   string       `yaml:"file"` // empty writes to stdout
+}
+
+// SortOrder is the order violations are reported in
+type SortOrder string
+
+const (
+	SortByFile SortOrder = "file"
+	SortByType SortOrder = "type"
+	SortByTag  SortOrder = "tag"
 )
+
+// SortOrders are the supported sort orders
+var SortOrders = []SortOrder{SortByFile, SortByType, SortByTag}
+
+// Thresholds decide whether violations fail the run
+type Thresholds struct {
+	MaxViolations int     // violations allowed at or above fail-on, -1 for no limit
+	MinCompliance float64 // percentage of resources that must be compliant, 0 for no minimum
+}
+
+// ParseError is a file that could not be read or parsed, so was not checked
+type ParseError struct {
+	FilePath string
+	Line     int // where the parser failed, 1 when unknown
+	Message  string
+	Severity Severity // warning, or error when strict
+}
+
+// OutputOptions are settings for individual formatters
+type OutputOptions struct {
+	MarkdownMaxSize          int                 // bytes, 0 is unlimited
+	CheckstyleIncludeSkipped bool                // report ignored findings at info severity
+	IncludeCompliant         bool                // list compliant resources and their effective tags
+	JUnitPerFile             bool                // a JUnit suite per file, with every resource checked as a test case
+	FailOn                   Severity            // findings at or above it fail, eg a JUnit test case
+	RequiredTags             []string            // required tag keys, sorted, for coverage reporting
+	Severities               map[string]Severity // by required tag key, for sarif rule levels
+	SortBy                   SortOrder
+	TemplatePath             string // text/template file for template output
+	GitHubSummaryFile        string // $GITHUB_STEP_SUMMARY, github output appends a markdown job summary when set
+}
@@ -3,7 +3,6 @@ package terraform
 import (
 	"fmt"
 	"log"
-	"sort"
 	"strings"
 
 	"github.com/hashicorp/hcl/v2/hclparse"
@@ -23,8 +22,7 @@ func processDefaultTags(tfFiles []tfFile, tfContext *TerraformContext, caseInsen
 	for _, tf := range tfFiles {
 		file, diags := parser.ParseHCLFile(tf.path)
 		if diags.HasErrors() || file == nil {
-			log.Printf("Error parsing %s during default tag scan: %v\n", tf.path, diags)
-			continue
+			continue // reported as a parse error by processFile
 		}
 
 		syntaxBody, ok := file.Body.(*hclsyntax.Body)
@@ -43,17 +41,13 @@ func processDefaultTags(tfFiles []tfFile, tfContext *TerraformContext, caseInsen
 func processProviders(body *hclsyntax.Body, defaultTags *DefaultTags, tfContext *TerraformContext, caseInsensitive bool) {
 	for _, block := range body.Blocks {
 		if block.Type == "provider" && len(block.Labels) > 0 {
-			providerID := getProviderID(block, caseInsensitive)   // handle ID
-			tags := getDefaultTags(block, tfContext, caseInsensitive) // handle tags
+			providerID := getProviderID(block, caseInsensitive) // handle ID
+			tags := getDefaultTags(block, tfContext)            // handle tags
 
 			if len(tags) > 0 {
-				var keys []string
-				for key := range tags {
-					keys = append(keys, key) // remove bool element of tag map
-				}
-				sort.Strings(keys)
+				keys := shared.TagKeys(shared.NormalizeTags(tags, caseInsensitive))
 				fmt.Printf("Found Terraform default tags for provider %s: [%v]\n", providerID, strings.Join(keys, ", "))
-				defaultTags.LiteralTags[providerID] = tags
+				defaultTags.LiteralTags[providerID] = tags // keys keep their original case, for key checks
 
 			}
 		}
@@ -88,7 +82,7 @@ func normalizeProviderID(providerName, alias string, caseInsensitive bool) strin
 }
 
 // getDefaultTags returns the default_tags on a provider block.
-func getDefaultTags(block *hclsyntax.Block, tfContext *TerraformContext, caseInsensitive bool) shared.TagMap { // Add tfContext param
+func getDefaultTags(block *hclsyntax.Block, tfContext *TerraformContext) shared.TagMap {
 	for _, subBlock := range block.Body.Blocks {
 		if subBlock.Type == "default_tags" {
 			if tagsAttr, exists := subBlock.Body.Attributes["tags"]; exists {
@@ -124,8 +118,7 @@ func getDefaultTags(block *hclsyntax.Block, tfContext *TerraformContext, caseIns
 						}
 					}
 
-					effectiveKey := shared.NormalizeCase(key, caseInsensitive)
-					evalTags[effectiveKey] = []string{valStr}
+					evalTags[key] = []string{valStr}
 				}
 				return evalTags
 			}
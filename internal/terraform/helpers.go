@@ -1,6 +1,7 @@
 package terraform
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"log"
@@ -9,7 +10,6 @@ import (
 
 	"github.com/hashicorp/hcl/v2"
 	"github.com/hashicorp/hcl/v2/hclsyntax"
-	"github.com/jakebark/tag-nag/internal/config"
 	"github.com/jakebark/tag-nag/internal/shared"
 	"github.com/zclconf/go-cty/cty"
 	ctyjson "github.com/zclconf/go-cty/cty/json"
@@ -42,26 +42,82 @@ func traversalToString(expr hcl.Expression, caseInsensitive bool) string {
 	return ""
 }
 
-// mergeTags combines multiple tag maps
-func mergeTags(tagMaps ...shared.TagMap) shared.TagMap {
-	merged := make(shared.TagMap)
-	for _, m := range tagMaps {
-		for k, v := range m {
-			merged[k] = v
+// SkipResource returns the ignore comment for a resource block, if present
+// comments directly above the block, on its header line or at the top of its body are checked
+func SkipResource(block *hclsyntax.Block, tokens hclsyntax.Tokens) (shared.Ignore, bool, error) {
+	for _, comment := range blockComments(block, tokens) {
+		ignore, found, err := shared.ParseIgnore(comment.Text)
+		if found && !ignore.All { // ignore-all applies to the whole file
+			ignore.Line = comment.Line
+			return ignore, true, err
 		}
 	}
-	return merged
+	return shared.Ignore{}, false, nil
 }
 
-// SkipResource determines if a resource block should be skipped
-func SkipResource(block *hclsyntax.Block, lines []string) bool {
-	index := block.DefRange().Start.Line
-	if index < len(lines) {
-		if strings.Contains(lines[index], config.TagNagIgnore) {
-			return true
+// blockComments returns the comments leading up to a block, and those before the first item in its body
+func blockComments(block *hclsyntax.Block, tokens hclsyntax.Tokens) []shared.Comment {
+	var comments []shared.Comment
+
+	start := block.DefRange().Start.Byte
+	openBrace := block.OpenBraceRange.Start.Byte
+	for i, token := range tokens {
+		switch token.Range.Start.Byte {
+		case start:
+			comments = append(comments, commentsBefore(tokens, i)...)
+		case openBrace:
+			comments = append(comments, commentsAfter(tokens, i)...)
+		}
+	}
+	return comments
+}
+
+// commentsBefore walks back from a token over comments and blank lines
+// a trailing comment on the previous item's line belongs to that item
+func commentsBefore(tokens hclsyntax.Tokens, index int) []shared.Comment {
+	var comments []shared.Comment
+	earliest := -1
+	i := index - 1
+	for ; i >= 0; i-- {
+		if tokens[i].Type == hclsyntax.TokenComment {
+			comments = append(comments, tokenComment(tokens[i]))
+			earliest = i
+		} else if tokens[i].Type != hclsyntax.TokenNewline {
+			break
+		}
+	}
+	if i >= 0 && earliest != -1 && tokens[earliest].Range.Start.Line == tokens[i].Range.End.Line {
+		comments = comments[:len(comments)-1]
+	}
+	return comments
+}
+
+// commentsAfter walks forward from a token over comments and blank lines
+func commentsAfter(tokens hclsyntax.Tokens, index int) []shared.Comment {
+	var comments []shared.Comment
+	for i := index + 1; i < len(tokens); i++ {
+		if tokens[i].Type == hclsyntax.TokenComment {
+			comments = append(comments, tokenComment(tokens[i]))
+		} else if tokens[i].Type != hclsyntax.TokenNewline {
+			break
 		}
 	}
-	return false
+	return comments
+}
+
+// fileComments returns every comment in a file
+func fileComments(tokens hclsyntax.Tokens) []shared.Comment {
+	var comments []shared.Comment
+	for _, token := range tokens {
+		if token.Type == hclsyntax.TokenComment {
+			comments = append(comments, tokenComment(token))
+		}
+	}
+	return comments
+}
+
+func tokenComment(token hclsyntax.Token) shared.Comment {
+	return shared.Comment{Text: string(token.Bytes), Line: token.Range.Start.Line}
 }
 
 func convertCtyValueToString(val cty.Value) (string, error) {
@@ -140,3 +196,67 @@ func loadTaggableResources(providerAddr string) map[string]bool {
 
 	return taggable
 }
+
+// tagFix returns where missing tags can be added to a resource block, or nil when the tags can't be edited safely
+// eg tags built with merge() or a variable
+func tagFix(block *hclsyntax.Block, src []byte) *shared.TagFix {
+	attr, exists := block.Body.Attributes["tags"]
+	if !exists {
+		closeBrace := block.CloseBraceRange.Start
+		if closeBrace.Line <= block.OpenBraceRange.Start.Line {
+			return nil // single line block
+		}
+		indent := lineIndent(src, closeBrace) + "  "
+		if first, ok := firstBodyItem(block.Body); ok {
+			indent = lineIndent(src, first)
+		}
+		return &shared.TagFix{Line: closeBrace.Line, Indent: indent, Style: shared.FixHCLAttribute}
+	}
+
+	object, ok := attr.Expr.(*hclsyntax.ObjectConsExpr)
+	if !ok {
+		return nil
+	}
+	closeBrace := object.SrcRange.End
+	if closeBrace.Line <= object.SrcRange.Start.Line {
+		return nil // single line object
+	}
+	indent := lineIndent(src, closeBrace) + "  "
+	if len(object.Items) > 0 {
+		if object.Items[len(object.Items)-1].ValueExpr.Range().End.Line == closeBrace.Line {
+			return nil // closing brace shares a line with an item
+		}
+		indent = lineIndent(src, object.Items[0].KeyExpr.Range().Start)
+	}
+	return &shared.TagFix{Line: closeBrace.Line, Indent: indent, Style: shared.FixHCLObject}
+}
+
+// firstBodyItem returns the start of the first attribute or block in a body
+func firstBodyItem(body *hclsyntax.Body) (hcl.Pos, bool) {
+	var first hcl.Pos
+	found := false
+	for _, attr := range body.Attributes {
+		if start := attr.SrcRange.Start; !found || start.Byte < first.Byte {
+			first, found = start, true
+		}
+	}
+	for _, block := range body.Blocks {
+		if start := block.DefRange().Start; !found || start.Byte < first.Byte {
+			first, found = start, true
+		}
+	}
+	return first, found
+}
+
+// lineIndent returns the leading whitespace of the line a position is on
+func lineIndent(src []byte, pos hcl.Pos) string {
+	if pos.Byte > len(src) {
+		return ""
+	}
+	start := bytes.LastIndexByte(src[:pos.Byte], '\n') + 1
+	end := start
+	for end < len(src) && (src[end] == ' ' || src[end] == '\t') {
+		end++
+	}
+	return string(src[start:end])
+}
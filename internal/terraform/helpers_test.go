@@ -1,12 +1,11 @@
 package terraform
 
 import (
-	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/hashicorp/hcl/v2"
 	"github.com/hashicorp/hcl/v2/hclsyntax"
-	"github.com/jakebark/tag-nag/internal/shared"
 	"github.com/zclconf/go-cty/cty"
 )
 
@@ -53,65 +52,6 @@ func TestTraversalToString(t *testing.T) {
 	}
 }
 
-func TestMergeTags(t *testing.T) {
-	testCases := []struct {
-		name     string
-		inputs   []shared.TagMap
-		expected shared.TagMap
-	}{
-		{
-			name:     "empty",
-			inputs:   []shared.TagMap{},
-			expected: shared.TagMap{},
-		},
-		{
-			name: "key",
-			inputs: []shared.TagMap{
-				{"Environment": {}},
-			},
-			expected: shared.TagMap{"Environment": {}},
-		},
-		{
-			name:     "key and value",
-			inputs:   []shared.TagMap{{"Environment": {"Dev"}}},
-			expected: shared.TagMap{"Environment": {"Dev"}},
-		},
-		{
-			name: "multiple keys and values",
-			inputs: []shared.TagMap{
-				{"Environment": {"Dev"}},
-				{"Owner": {"Prod"}},
-			},
-			expected: shared.TagMap{"Environment": {"Dev"}, "Owner": {"Prod"}},
-		},
-		{
-			name: "overlapping values, last wins",
-			inputs: []shared.TagMap{
-				{"Environment": {"Dev"}, "Owner": {"jakebark"}},
-				{"Owner": {"Jake"}, "CostCenter": {"C-01"}},
-			},
-			expected: shared.TagMap{"Environment": {"Dev"}, "Owner": {"Jake"}, "CostCenter": {"C-01"}},
-		},
-		{
-			name: "overlapping empty value, last wins",
-			inputs: []shared.TagMap{
-				{"Environment": {"Dev"}},
-				{"Environment": {}},
-			},
-			expected: shared.TagMap{"Environment": {}},
-		},
-	}
-
-	for _, tc := range testCases {
-		t.Run(tc.name, func(t *testing.T) {
-			actual := mergeTags(tc.inputs...)
-			if !reflect.DeepEqual(actual, tc.expected) {
-				t.Errorf("mergeTags() = %v; want %v", actual, tc.expected)
-			}
-		})
-	}
-}
-
 func TestConvertCtyValueToString(t *testing.T) {
 	tests := []struct {
 		name    string
@@ -183,3 +123,148 @@ func TestConvertCtyValueToString(t *testing.T) {
 		})
 	}
 }
+
+func TestSkipResource(t *testing.T) {
+	testCases := []struct {
+		name           string
+		code           string
+		expectedFound  bool
+		expectedReason string
+	}{
+		{
+			name:          "no comment",
+			code:          "resource \"aws_s3_bucket\" \"this\" {\n  bucket = \"test\"\n}\n",
+			expectedFound: false,
+		},
+		{
+			name:          "first line inside block",
+			code:          "resource \"aws_s3_bucket\" \"this\" {\n  #tag-nag ignore\n  bucket = \"test\"\n}\n",
+			expectedFound: true,
+		},
+		{
+			name:           "header line",
+			code:           "resource \"aws_s3_bucket\" \"this\" { # tag-nag ignore reason=\"header\"\n  bucket = \"test\"\n}\n",
+			expectedFound:  true,
+			expectedReason: "header",
+		},
+		{
+			name:           "above block, after blank line and other comments",
+			code:           "// tag-nag ignore reason=\"above\"\n\n# owned by platform\nresource \"aws_s3_bucket\" \"this\" {\n  bucket = \"test\"\n}\n",
+			expectedFound:  true,
+			expectedReason: "above",
+		},
+		{
+			name:          "inside block after blank line",
+			code:          "resource \"aws_s3_bucket\" \"this\" {\n\n  /* tag-nag ignore */\n  bucket = \"test\"\n}\n",
+			expectedFound: true,
+		},
+		{
+			name:          "trailing comment on previous block",
+			code:          "resource \"aws_s3_bucket\" \"that\" {\n  bucket = \"test\"\n} # tag-nag ignore\nresource \"aws_s3_bucket\" \"this\" {\n  bucket = \"test\"\n}\n",
+			expectedFound: false,
+		},
+		{
+			name:          "later in the block",
+			code:          "resource \"aws_s3_bucket\" \"this\" {\n  bucket = \"test\"\n  #tag-nag ignore\n}\n",
+			expectedFound: false,
+		},
+		{
+			name:          "inside a string",
+			code:          "resource \"aws_s3_bucket\" \"this\" {\n  bucket = \"#tag-nag ignore\"\n}\n",
+			expectedFound: false,
+		},
+		{
+			name:          "ignore-all is file level",
+			code:          "resource \"aws_s3_bucket\" \"this\" {\n  #tag-nag ignore-all\n  bucket = \"test\"\n}\n",
+			expectedFound: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			file, diags := hclsyntax.ParseConfig([]byte(tc.code), "test.tf", hcl.InitialPos)
+			if diags.HasErrors() {
+				t.Fatalf("Failed to parse HCL: %v", diags)
+			}
+			tokens, _ := hclsyntax.LexConfig([]byte(tc.code), "test.tf", hcl.InitialPos)
+
+			blocks := file.Body.(*hclsyntax.Body).Blocks
+			block := blocks[len(blocks)-1] // the resource under test is always last
+			ignore, found, err := SkipResource(block, tokens)
+			if err != nil {
+				t.Fatalf("SkipResource() error = %v", err)
+			}
+			if found != tc.expectedFound {
+				t.Errorf("SkipResource() found = %v, want %v", found, tc.expectedFound)
+			}
+			if ignore.Reason != tc.expectedReason {
+				t.Errorf("SkipResource() reason = %q, want %q", ignore.Reason, tc.expectedReason)
+			}
+		})
+	}
+}
+
+func TestTagFix(t *testing.T) {
+	testCases := []struct {
+		name     string
+		code     string
+		expected string // code with an Owner tag added, empty when there should be no fix
+	}{
+		{
+			name:     "no tags",
+			code:     "resource \"aws_s3_bucket\" \"this\" {\n  bucket = \"test\"\n}\n",
+			expected: "resource \"aws_s3_bucket\" \"this\" {\n  bucket = \"test\"\n  tags = {\n    Owner = \"\"\n  }\n}\n",
+		},
+		{
+			name:     "empty body",
+			code:     "resource \"aws_s3_bucket\" \"this\" {\n}\n",
+			expected: "resource \"aws_s3_bucket\" \"this\" {\n  tags = {\n    Owner = \"\"\n  }\n}\n",
+		},
+		{
+			name:     "tags object, tab indented",
+			code:     "resource \"aws_s3_bucket\" \"this\" {\n\ttags = {\n\t\tProject = \"web\"\n\t}\n}\n",
+			expected: "resource \"aws_s3_bucket\" \"this\" {\n\ttags = {\n\t\tProject = \"web\"\n\t\tOwner = \"\"\n\t}\n}\n",
+		},
+		{
+			name: "single line tags object",
+			code: "resource \"aws_s3_bucket\" \"this\" {\n  tags = { Project = \"web\" }\n}\n",
+		},
+		{
+			name: "tags from a function",
+			code: "resource \"aws_s3_bucket\" \"this\" {\n  tags = merge(local.tags, {\n    Project = \"web\"\n  })\n}\n",
+		},
+		{
+			name: "single line block",
+			code: "resource \"aws_s3_bucket\" \"this\" {}\n",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			file, diags := hclsyntax.ParseConfig([]byte(tc.code), "test.tf", hcl.InitialPos)
+			if diags.HasErrors() {
+				t.Fatalf("Failed to parse HCL: %v", diags)
+			}
+
+			fix := tagFix(file.Body.(*hclsyntax.Body).Blocks[0], []byte(tc.code))
+			if fix == nil {
+				if tc.expected != "" {
+					t.Fatalf("tagFix() = nil, want a fix")
+				}
+				return
+			}
+			if tc.expected == "" {
+				t.Fatalf("tagFix() = %+v, want nil", fix)
+			}
+
+			lines := strings.SplitAfter(tc.code, "\n")
+			fixed := strings.Join(lines[:fix.Line-1], "") + fix.Text("Owner", "") + strings.Join(lines[fix.Line-1:], "")
+			if fixed != tc.expected {
+				t.Errorf("fixed code = %q, want %q", fixed, tc.expected)
+			}
+			if _, diags := hclsyntax.ParseConfig([]byte(fixed), "test.tf", hcl.InitialPos); diags.HasErrors() {
+				t.Errorf("fixed code does not parse: %v", diags)
+			}
+		})
+	}
+}
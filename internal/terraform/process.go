@@ -21,18 +21,20 @@ type tfFile struct {
 	info os.FileInfo
 }
 
-// ProcessDirectory walks all terraform files in directory
-func ProcessDirectory(directoryPath string, requiredTags map[string][]string, caseInsensitive bool, skip []string) []shared.Violation {
+// ProcessDirectory walks all terraform files in directory, returning violations, every resource checked, and files that could not be parsed
+func ProcessDirectory(directoryPath string, rules shared.Rules, caseInsensitive bool, skip []string) ([]shared.Violation, []shared.Resource, []shared.ParseError) {
 	hasFiles, err := scan(directoryPath)
 	if err != nil {
-		return nil
+		return nil, nil, nil
 	}
 	if !hasFiles {
-		return nil
+		return nil, nil, nil
 	}
 
 	// log.Println("Terraform files found\n")
 	var allViolations []shared.Violation
+	var allResources []shared.Resource
+	var allParseErrors []shared.ParseError
 
 	taggable := loadTaggableResources("registry.terraform.io/hashicorp/aws")
 	if taggable == nil {
@@ -49,11 +51,11 @@ func ProcessDirectory(directoryPath string, requiredTags map[string][]string, ca
 	tfFiles, err := collectFiles(directoryPath, skip)
 	if err != nil {
 		log.Printf("Error scanning directory %q: %v\n", directoryPath, err)
-		return nil
+		return nil, nil, nil
 	}
 
 	if len(tfFiles) == 0 {
-		return nil
+		return nil, nil, nil
 	}
 
 	// extract default tags from all files
@@ -61,11 +63,13 @@ func ProcessDirectory(directoryPath string, requiredTags map[string][]string, ca
 
 	// process resources for tag violations
 	for _, tf := range tfFiles {
-		violations := processFile(tf.path, requiredTags, &defaultTags, tfContext, caseInsensitive, taggable)
+		violations, resources, parseErrors := processFile(tf.path, rules, &defaultTags, tfContext, caseInsensitive, taggable)
 		allViolations = append(allViolations, violations...)
+		allResources = append(allResources, resources...)
+		allParseErrors = append(allParseErrors, parseErrors...)
 	}
 
-	return allViolations
+	return allViolations, allResources, allParseErrors
 }
 
 // collectFiles identifies all elligible terraform files
@@ -113,32 +117,52 @@ func skipDirectories(path string, info os.FileInfo, skip []string) bool {
 	return false
 }
 
-// processFile parses files looking for resources
-func processFile(filePath string, requiredTags shared.TagMap, defaultTags *DefaultTags, tfContext *TerraformContext, caseInsensitive bool, taggable map[string]bool) []shared.Violation {
+// processFile parses files looking for resources, returning parse errors when the file cannot be checked
+func processFile(filePath string, rules shared.Rules, defaultTags *DefaultTags, tfContext *TerraformContext, caseInsensitive bool, taggable map[string]bool) ([]shared.Violation, []shared.Resource, []shared.ParseError) {
 	data, err := os.ReadFile(filePath)
 	if err != nil {
-		log.Printf("Error reading %s: %v\n", filePath, err)
-		return nil
+		return nil, nil, []shared.ParseError{shared.NewParseError(filePath, 0, err.Error(), rules.Strict)}
 	}
-	content := string(data)
-	lines := strings.Split(content, "\n")
-
-	skipAll := strings.Contains(content, config.TagNagIgnoreAll)
 
 	parser := hclparse.NewParser()
 	file, diagnostics := parser.ParseHCLFile(filePath)
 
 	if diagnostics.HasErrors() {
-		log.Printf("Error parsing %s: %v\n", filePath, diagnostics)
-		return nil
+		return nil, nil, parseErrors(diagnostics, filePath, rules.Strict)
 	}
 
 	syntaxBody, ok := file.Body.(*hclsyntax.Body)
 	if !ok {
-		log.Printf("Parsing failed for %s\n", filePath)
-		return nil
+		return nil, nil, []shared.ParseError{shared.NewParseError(filePath, 0, "not HCL native syntax", rules.Strict)}
 	}
 
-	violations := checkResourcesForTags(syntaxBody, requiredTags, defaultTags, tfContext, caseInsensitive, lines, skipAll, taggable, filePath)
-	return violations
+	// comments are not part of the syntax tree, so find ignore comments from the tokens
+	tokens, _ := hclsyntax.LexConfig(data, filePath, hcl.InitialPos)
+	fileIgnore, err := shared.FindIgnoreAll(fileComments(tokens))
+	if err != nil {
+		log.Printf("Invalid ignore-all comment in %s: %v\n", filePath, err)
+	}
+
+	violations, resources := checkResourcesForTags(syntaxBody, rules, defaultTags, tfContext, caseInsensitive, data, tokens, fileIgnore, taggable, filePath)
+	return violations, resources, nil
+}
+
+// parseErrors reports each error diagnostic at the line it is on
+func parseErrors(diagnostics hcl.Diagnostics, filePath string, strict bool) []shared.ParseError {
+	var errors []shared.ParseError
+	for _, diag := range diagnostics {
+		if diag.Severity != hcl.DiagError {
+			continue
+		}
+		line := 0
+		if diag.Subject != nil {
+			line = diag.Subject.Start.Line
+		}
+		message := diag.Summary
+		if diag.Detail != "" {
+			message += ": " + diag.Detail
+		}
+		errors = append(errors, shared.NewParseError(filePath, line, message, strict))
+	}
+	return errors
 }
@@ -0,0 +1,38 @@
+package terraform
+
+import (
+	"testing"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/jakebark/tag-nag/internal/shared"
+)
+
+func TestParseErrors(t *testing.T) {
+	src := `resource "aws_s3_bucket" "this" {
+  bucket = "my-bucket"
+  tags = {
+    Owner = "jake"
+}
+`
+	_, diagnostics := hclsyntax.ParseConfig([]byte(src), "main.tf", hcl.InitialPos)
+	if !diagnostics.HasErrors() {
+		t.Fatal("expected the source not to parse")
+	}
+
+	errors := parseErrors(diagnostics, "main.tf", true)
+	if len(errors) == 0 {
+		t.Fatal("parseErrors() returned no errors")
+	}
+	for _, e := range errors {
+		if e.FilePath != "main.tf" || e.Line < 1 {
+			t.Errorf("parse error at %s:%d, want main.tf with a line", e.FilePath, e.Line)
+		}
+		if e.Severity != shared.SeverityError {
+			t.Errorf("severity = %q, want a strict parse error", e.Severity)
+		}
+		if e.Message == "" {
+			t.Errorf("parse error has no message")
+		}
+	}
+}
@@ -32,10 +32,8 @@ func buildTagContext(directoryPath string) (*TerraformContext, error) {
 			}
 		}
 		if !info.IsDir() && filepath.Ext(path) == ".tf" {
-			file, diags := parser.ParseHCLFile(path)
-			if diags.HasErrors() {
-				log.Printf("Error parsing HCL file %s: %v\n", path, diags)
-			}
+			// parse errors are reported by processFile
+			file, _ := parser.ParseHCLFile(path)
 			if file != nil {
 				parsedFiles[path] = file
 			}
@@ -3,15 +3,20 @@ package terraform
 import (
 	"log"
 	"strings"
+	"time"
 
 	"github.com/hashicorp/hcl/v2/hclsyntax"
 	"github.com/jakebark/tag-nag/internal/shared"
 	"github.com/zclconf/go-cty/cty"
 )
 
-// checkResourcesForTags inspects resource blocks and returns violations
-func checkResourcesForTags(body *hclsyntax.Body, requiredTags shared.TagMap, defaultTags *DefaultTags, tfContext *TerraformContext, caseInsensitive bool, fileLines []string, skipAll bool, taggable map[string]bool, filePath string) []shared.Violation {
+// checkResourcesForTags inspects resource blocks and returns violations, and every resource checked
+func checkResourcesForTags(body *hclsyntax.Body, rules shared.Rules, defaultTags *DefaultTags, tfContext *TerraformContext, caseInsensitive bool, src []byte, tokens hclsyntax.Tokens, fileIgnore *shared.Ignore, taggable map[string]bool, filePath string) ([]shared.Violation, []shared.Resource) {
 	var violations []shared.Violation
+	var resources []shared.Resource
+	var attachedIgnores []shared.Ignore
+	var uncheckedIgnores []shared.Ignore
+	fileIgnoreUsed := false
 
 	for _, block := range body.Blocks {
 		if block.Type != "resource" || len(block.Labels) < 2 { // skip anything without 2 labels eg "aws_s3_bucket" and "this"
@@ -21,7 +26,22 @@ func checkResourcesForTags(body *hclsyntax.Body, requiredTags shared.TagMap, def
 		resourceType := block.Labels[0] // aws_s3_bucket
 		resourceName := block.Labels[1] // this
 
+		var resourceIgnore *shared.Ignore
+		ignore, found, err := SkipResource(block, tokens)
+		if err != nil {
+			log.Printf("Invalid ignore comment for %s.%s in %s: %v\n", resourceType, resourceName, filePath, err)
+		}
+		if found {
+			attachedIgnores = append(attachedIgnores, ignore)
+			if err == nil {
+				resourceIgnore = &ignore
+			}
+		}
+
 		if !strings.HasPrefix(resourceType, "aws_") {
+			if found {
+				uncheckedIgnores = append(uncheckedIgnores, ignore) // the resource is not checked, so it can suppress nothing
+			}
 			continue
 		}
 
@@ -39,6 +59,9 @@ func checkResourcesForTags(body *hclsyntax.Body, requiredTags shared.TagMap, def
 
 		if !isTaggable {
 			// log.Printf("Skipping non-taggable resource type: %s", resourceType)
+			if found {
+				uncheckedIgnores = append(uncheckedIgnores, ignore) // the resource is not checked, so it can suppress nothing
+			}
 			continue
 		}
 
@@ -48,25 +71,45 @@ func checkResourcesForTags(body *hclsyntax.Body, requiredTags shared.TagMap, def
 			providerEvalTags = make(shared.TagMap)
 		}
 
-		resourceEvalTags := findTags(block, tfContext, caseInsensitive)
-		effectiveTags := mergeTags(providerEvalTags, resourceEvalTags)
+		resourceEvalTags := findTags(block, tfContext)
 
-		missingTags := shared.FilterMissingTags(requiredTags, effectiveTags, caseInsensitive)
-		if len(missingTags) > 0 {
-			violation := shared.Violation{
-				ResourceType: resourceType,
-				ResourceName: resourceName,
-				Line:         block.DefRange().Start.Line,
-				MissingTags:  missingTags,
-				FilePath:     filePath,
-			}
-			if skipAll || SkipResource(block, fileLines) {
-				violation.Skip = true
+		findings := shared.CheckTags(rules, providerEvalTags, resourceEvalTags, caseInsensitive)
+		for i := range findings {
+			if findings[i].FromDefaultTags {
+				findings[i].InheritedFrom = "default_tags"
 			}
+		}
+		violation := shared.Violation{
+			ResourceType: resourceType,
+			ResourceName: resourceName,
+			Line:         block.DefRange().Start.Line,
+			EndLine:      block.Range().End.Line,
+			Findings:     findings,
+			FilePath:     filePath,
+			Fix:          tagFix(block, src),
+		}
+		if shared.ApplyIgnores(&violation, resourceIgnore, fileIgnore, rules, time.Now()) {
+			fileIgnoreUsed = true
+		}
+		violation.MissingTags = shared.MissingTags(violation.Findings) // after ignores, so suppressed findings are left out
+		violation.KeyIssues = shared.KeyIssues(violation.Findings)
+		resources = append(resources, shared.Resource{
+			ResourceType: resourceType,
+			ResourceName: resourceName,
+			Line:         block.DefRange().Start.Line,
+			FilePath:     filePath,
+			Tags:         shared.MergeTags(providerEvalTags, resourceEvalTags),
+			Compliant:    violation.Compliant(rules.FailOn), // after ignores, so ignored and below --fail-on findings are left out
+		})
+		if len(violation.Findings) > 0 {
 			violations = append(violations, violation)
 		}
 	}
-	return violations
+
+	if rules.ReportUnusedIgnores {
+		violations = append(violations, shared.UnusedIgnoreViolations(fileComments(tokens), attachedIgnores, uncheckedIgnores, fileIgnore, fileIgnoreUsed, filePath)...)
+	}
+	return violations, resources
 }
 
 // getResourceProvider determines the provider for a resource block
@@ -92,7 +135,7 @@ func getResourceProvider(block *hclsyntax.Block, caseInsensitive bool) string {
 }
 
 // findTags returns tag map from a resource block (with extractTags), if it has tags
-func findTags(block *hclsyntax.Block, tfContext *TerraformContext, caseInsensitive bool) shared.TagMap {
+func findTags(block *hclsyntax.Block, tfContext *TerraformContext) shared.TagMap {
 	evalTags := make(shared.TagMap)
 	if attr, exists := block.Body.Attributes["tags"]; exists {
 
@@ -133,8 +176,7 @@ func findTags(block *hclsyntax.Block, tfContext *TerraformContext, caseInsensiti
 				}
 			}
 
-			effectiveKey := shared.NormalizeCase(key, caseInsensitive)
-			evalTags[effectiveKey] = []string{valStr}
+			evalTags[key] = []string{valStr}
 		}
 	}
 	return evalTags
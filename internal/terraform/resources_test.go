@@ -2,7 +2,6 @@ package terraform
 
 import (
 	"sort"
-	"strings"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
@@ -58,7 +57,7 @@ func TestCheckResourcesForTags_Taggability(t *testing.T) {
 		"Owner":       {},
 		"Environment": {},
 	}
-	lines := strings.Split(tfCode, "\n")
+	tokens, _ := hclsyntax.LexConfig([]byte(tfCode), "test.tf", hcl.InitialPos)
 
 	t.Run("With Taggability Filter", func(t *testing.T) {
 		taggableMap := map[string]bool{
@@ -72,7 +71,7 @@ func TestCheckResourcesForTags_Taggability(t *testing.T) {
 		mockCtx := &TerraformContext{EvalContext: &hcl.EvalContext{Variables: make(map[string]cty.Value)}}
 		mockDefaults := &DefaultTags{LiteralTags: make(map[string]shared.TagMap)}
 
-		violations := checkResourcesForTags(body, requiredTags, mockDefaults, mockCtx, false, lines, false, taggableMap, "test.tf")
+		violations, _ := checkResourcesForTags(body, shared.Rules{RequiredTags: requiredTags}, mockDefaults, mockCtx, false, []byte(tfCode), tokens, nil, taggableMap, "test.tf")
 
 		expectedViolations := []shared.Violation{
 			{ResourceType: "aws_s3_bucket", ResourceName: "taggable_bucket", Line: 2, MissingTags: []string{"Environment"}, FilePath: "test.tf"},
@@ -82,7 +81,7 @@ func TestCheckResourcesForTags_Taggability(t *testing.T) {
 		sortViolations(violations)
 		sortViolations(expectedViolations) // Sort missing tags within each violation for stable comparison
 
-		if diff := cmp.Diff(expectedViolations, violations, cmpopts.IgnoreUnexported(shared.Violation{})); diff != "" {
+		if diff := cmp.Diff(expectedViolations, violations, cmpopts.IgnoreUnexported(shared.Violation{}), cmpopts.IgnoreFields(shared.Violation{}, "Findings", "EndLine", "Fix")); diff != "" {
 			t.Errorf("checkResourcesForTags with filter mismatch (-want +got):\n%s", diff)
 		}
 	})
@@ -93,7 +92,7 @@ func TestCheckResourcesForTags_Taggability(t *testing.T) {
 		mockCtx := &TerraformContext{EvalContext: &hcl.EvalContext{Variables: make(map[string]cty.Value)}}
 		mockDefaults := &DefaultTags{LiteralTags: make(map[string]shared.TagMap)}
 
-		violations := checkResourcesForTags(body, requiredTags, mockDefaults, mockCtx, false, lines, false, taggableMap, "test.tf")
+		violations, _ := checkResourcesForTags(body, shared.Rules{RequiredTags: requiredTags}, mockDefaults, mockCtx, false, []byte(tfCode), tokens, nil, taggableMap, "test.tf")
 
 		expectedViolations := []shared.Violation{
 			{ResourceType: "aws_s3_bucket", ResourceName: "taggable_bucket", Line: 2, MissingTags: []string{"Environment"}, FilePath: "test.tf"},
@@ -103,7 +102,7 @@ func TestCheckResourcesForTags_Taggability(t *testing.T) {
 		sortViolations(violations)
 		sortViolations(expectedViolations)
 
-		if diff := cmp.Diff(expectedViolations, violations, cmpopts.IgnoreUnexported(shared.Violation{})); diff != "" {
+		if diff := cmp.Diff(expectedViolations, violations, cmpopts.IgnoreUnexported(shared.Violation{}), cmpopts.IgnoreFields(shared.Violation{}, "Findings", "EndLine", "Fix")); diff != "" {
 			t.Errorf("checkResourcesForTags without filter mismatch (-want +got):\n%s", diff)
 		}
 	})
@@ -119,7 +118,7 @@ func TestCheckResourcesForTags_Taggability(t *testing.T) {
 		mockCtx := &TerraformContext{EvalContext: &hcl.EvalContext{Variables: make(map[string]cty.Value)}}
 		mockDefaults := &DefaultTags{LiteralTags: make(map[string]shared.TagMap)}
 
-		violations := checkResourcesForTags(body, requiredTags, mockDefaults, mockCtx, false, lines, false, taggableMap, "test.tf")
+		violations, _ := checkResourcesForTags(body, shared.Rules{RequiredTags: requiredTags}, mockDefaults, mockCtx, false, []byte(tfCode), tokens, nil, taggableMap, "test.tf")
 
 		expectedViolations := []shared.Violation{
 			{ResourceType: "aws_s3_bucket", ResourceName: "taggable_bucket", Line: 2, MissingTags: []string{"Environment"}, FilePath: "test.tf"},
@@ -130,12 +129,75 @@ func TestCheckResourcesForTags_Taggability(t *testing.T) {
 		sortViolations(violations)
 		sortViolations(expectedViolations)
 
-		if diff := cmp.Diff(expectedViolations, violations, cmpopts.IgnoreUnexported(shared.Violation{})); diff != "" {
+		if diff := cmp.Diff(expectedViolations, violations, cmpopts.IgnoreUnexported(shared.Violation{}), cmpopts.IgnoreFields(shared.Violation{}, "Findings", "EndLine", "Fix")); diff != "" {
 			t.Errorf("checkResourcesForTags with incomplete filter mismatch (-want +got):\n%s", diff)
 		}
 	})
 }
 
+func TestCheckResourcesForTags_KeyIssues(t *testing.T) {
+	parser := hclparse.NewParser()
+
+	tfCode := `
+		resource "aws_s3_bucket" "this" {
+		  tags = {
+			owner       = "test-user"
+			Environment = "dev"
+		  }
+		}
+	`
+
+	file, diags := parser.ParseHCL([]byte(tfCode), "test.tf")
+	if diags.HasErrors() {
+		t.Fatalf("Failed to parse test HCL: %v", diags)
+	}
+	body := file.Body.(*hclsyntax.Body)
+	tokens, _ := hclsyntax.LexConfig([]byte(tfCode), "test.tf", hcl.InitialPos)
+
+	mockCtx := &TerraformContext{EvalContext: &hcl.EvalContext{Variables: make(map[string]cty.Value)}}
+	mockDefaults := &DefaultTags{LiteralTags: map[string]shared.TagMap{
+		"aws": {"Owner": {"platform"}},
+	}}
+
+	testCases := []struct {
+		name            string
+		keyStyle        shared.KeyStyle
+		caseInsensitive bool
+		expected        []string
+	}{
+		{
+			name:     "case duplicate with default tags",
+			expected: []string{"Owner/owner (case duplicate)"},
+		},
+		{
+			name:            "case duplicate with default tags, case insensitive",
+			caseInsensitive: true,
+			expected:        []string{"Owner/owner (case duplicate)"},
+		},
+		{
+			name:     "key style",
+			keyStyle: shared.KeyStyle{Case: "PascalCase"},
+			expected: []string{"Owner/owner (case duplicate)", "owner (not PascalCase)"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			requiredTags := shared.TagMap{"Owner": {}}
+			violations, _ := checkResourcesForTags(body, shared.Rules{RequiredTags: requiredTags, KeyStyle: tc.keyStyle}, mockDefaults, mockCtx, tc.caseInsensitive, []byte(tfCode), tokens, nil, nil, "test.tf")
+			if len(violations) != 1 {
+				t.Fatalf("Expected 1 violation, got %d", len(violations))
+			}
+			if diff := cmp.Diff(tc.expected, violations[0].KeyIssues); diff != "" {
+				t.Errorf("KeyIssues mismatch (-want +got):\n%s", diff)
+			}
+			if len(violations[0].MissingTags) != 0 {
+				t.Errorf("Expected no missing tags, got %v", violations[0].MissingTags)
+			}
+		})
+	}
+}
+
 // Helper to sort violations for consistent comparison
 func sortViolations(violations []shared.Violation) {
 	for i := range violations {
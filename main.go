@@ -21,12 +21,20 @@ func main() {
 		log.Printf("\033[33mScanning: %s\033[0m\n", userInput.Directory)
 	}
 
-	tfViolations := terraform.ProcessDirectory(userInput.Directory, userInput.RequiredTags, userInput.CaseInsensitive, userInput.Skip)
-	cfnViolations := cloudformation.ProcessDirectory(userInput.Directory, userInput.RequiredTags, userInput.CaseInsensitive, userInput.CfnSpecPath, userInput.Skip)
+	tfViolations, tfResources, tfParseErrors := terraform.ProcessDirectory(userInput.Directory, userInput.Rules, userInput.CaseInsensitive, userInput.Skip)
+	cfnViolations, cfnResources, cfnParseErrors := cloudformation.ProcessDirectory(userInput.Directory, userInput.Rules, userInput.CaseInsensitive, userInput.CfnSpecPath, userInput.Skip, userInput.CfnInclude, userInput.StackTags)
 
 	var allViolations []shared.Violation
 	allViolations = append(allViolations, tfViolations...)
 	allViolations = append(allViolations, cfnViolations...)
 
-	output.ProcessOutput(allViolations, userInput.OutputFormat, userInput.DryRun, userInput.OutputFile)
+	var allResources []shared.Resource
+	allResources = append(allResources, tfResources...)
+	allResources = append(allResources, cfnResources...)
+
+	var allParseErrors []shared.ParseError
+	allParseErrors = append(allParseErrors, tfParseErrors...)
+	allParseErrors = append(allParseErrors, cfnParseErrors...)
+
+	output.ProcessOutput(allViolations, allResources, allParseErrors, userInput.Outputs, userInput.DryRun, userInput.FailOn, userInput.Thresholds, userInput.OutputOptions)
 }
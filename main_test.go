@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 	"os/exec"
+	"path/filepath"
 	"strings"
 	"testing"
 )
@@ -66,7 +67,7 @@ func TestInputs(t *testing.T) {
 			name:             "no dir",
 			filePathOrDir:    "",
 			cliArgs:          []string{"--tags", "Owner"},
-			expectedExitCode: 1,
+			expectedExitCode: 2,
 			expectedError:    true,
 			expectedOutput:   []string{"Error: specify a directory or file to scan"},
 		},
@@ -74,7 +75,7 @@ func TestInputs(t *testing.T) {
 			name:             "no tags",
 			filePathOrDir:    "testdata/terraform/tags.tf",
 			cliArgs:          []string{"nonexistent.yml"},
-			expectedExitCode: 1,
+			expectedExitCode: 2,
 			expectedError:    true,
 			expectedOutput:   []string{"specify required tags using --tags or create a .tag-nag.yml config file"},
 		},
@@ -174,7 +175,15 @@ func TestTerraform(t *testing.T) {
 			cliArgs:          []string{"--tags", "Owner,Environment[test]"},
 			expectedExitCode: 1,
 			expectedError:    true,
-			expectedOutput:   []string{`aws_s3_bucket "this"`, "Missing tags: Environment[test]"},
+			expectedOutput:   []string{`aws_s3_bucket "this"`, "Invalid values: Environment[test]"},
+		},
+		{
+			name:             "missing tag value json",
+			filePathOrDir:    "testdata/terraform/tags.tf",
+			cliArgs:          []string{"--tags", "Owner,Environment[test]", "-o", "json"},
+			expectedExitCode: 1,
+			expectedError:    true,
+			expectedOutput:   []string{`"missing_tags": [`, `"rule_id": "invalid-tag-value"`, `"actual": "dev"`, `"severity": "error"`},
 		},
 		{
 			name:             "tag values case insensitive",
@@ -216,6 +225,14 @@ func TestTerraform(t *testing.T) {
 			expectedError:    false,
 			expectedOutput:   []string{"Found Terraform default tags for provider aws: [Project, Source]", "No tag violations found"},
 		},
+		{
+			name:             "provider tag value json",
+			filePathOrDir:    "testdata/terraform/provider.tf",
+			cliArgs:          []string{"--tags", "Owner,Source[other-repo]", "-o", "json"},
+			expectedExitCode: 1,
+			expectedError:    true,
+			expectedOutput:   []string{`"actual": "my-repo"`, `"from_default_tags": true`, `"inherited_from": "default_tags"`},
+		},
 		{
 			name:             "variable tags",
 			filePathOrDir:    "testdata/terraform/referenced_tags.tf",
@@ -270,7 +287,7 @@ func TestTerraform(t *testing.T) {
 			cliArgs:          []string{"--tags", "Owner,Environment,Project[112233],Source[not-my-repo]"},
 			expectedExitCode: 1,
 			expectedError:    true,
-			expectedOutput:   []string{`aws_s3_bucket "this"`, "Missing tags: Source[not-my-repo]"},
+			expectedOutput:   []string{`aws_s3_bucket "this"`, "Invalid values: Source[not-my-repo]"},
 		},
 		{
 			name:             "example repo",
@@ -296,6 +313,246 @@ func TestTerraform(t *testing.T) {
 			expectedError:    false,
 			expectedOutput:   []string{`aws_s3_bucket "this" skipped`},
 		},
+		{
+			name:             "ignore specific tags",
+			filePathOrDir:    "testdata/terraform/ignore_tags.tf",
+			cliArgs:          []string{"--tags", "Owner,Environment,Project"},
+			expectedExitCode: 1,
+			expectedError:    true,
+			expectedOutput:   []string{`Missing tags: Environment, Project (ignored: "legacy bucket")`, "ignore comment expired 2020-01-01"},
+		},
+		{
+			name:             "ignore specific tags json",
+			filePathOrDir:    "testdata/terraform/ignore_tags.tf",
+			cliArgs:          []string{"--tags", "Owner,Environment,Project", "-o", "json"},
+			expectedExitCode: 1,
+			expectedError:    true,
+			expectedOutput:   []string{"\"missing_tags\": [\n        \"Environment\"\n      ],", `"suppressed": true`},
+		},
+		{
+			name:             "require ignore reason",
+			filePathOrDir:    "testdata/terraform/ignore.tf",
+			cliArgs:          []string{"--tags", "Owner,Environment,Project", "--require-ignore-reason"},
+			expectedExitCode: 1,
+			expectedError:    true,
+			expectedOutput:   []string{"ignore comment has no reason"},
+		},
+		{
+			name:             "report unused ignores",
+			filePathOrDir:    "testdata/terraform/unused_ignore.tf",
+			cliArgs:          []string{"--tags", "Owner,Environment,Project", "--report-unused-ignores"},
+			expectedExitCode: 1,
+			expectedError:    true,
+			expectedOutput:   []string{`aws_s3_bucket "this" 🏷️  Ignore issues: ignore comment suppresses nothing`, "1: file 🏷️  Ignore issues: ignore comment is not on a resource"},
+		},
+		{
+			name:             "report ignores on unchecked resources",
+			filePathOrDir:    "testdata/ignore_unchecked",
+			cliArgs:          []string{"--tags", "Owner", "--report-unused-ignores"},
+			expectedExitCode: 1,
+			expectedError:    true,
+			expectedOutput:   []string{"Violation(s) in testdata/ignore_unchecked/main.tf\n  1: file 🏷️  Ignore issues: ignore comment suppresses nothing", "Violation(s) in testdata/ignore_unchecked/template.yaml\n  3: file 🏷️  Ignore issues: ignore comment suppresses nothing"},
+		},
+		{
+			name:             "unused ignores not reported by default",
+			filePathOrDir:    "testdata/terraform/unused_ignore.tf",
+			cliArgs:          []string{"--tags", "Owner,Environment,Project"},
+			expectedExitCode: 0,
+			expectedError:    false,
+			expectedOutput:   []string{"No tag violations found"},
+		},
+		{
+			name:             "markdown output",
+			filePathOrDir:    "testdata/terraform/tags.tf",
+			cliArgs:          []string{"--tags", "Owner,Environment,Project", "-o", "markdown"},
+			expectedExitCode: 1,
+			expectedError:    true,
+			expectedOutput:   []string{"## tag-nag report", "<summary><code>testdata/terraform/tags.tf</code>", "(testdata/terraform/tags.tf#L"},
+		},
+		{
+			name:             "csv output",
+			filePathOrDir:    "testdata/terraform/tags.tf",
+			cliArgs:          []string{"--tags", "Owner,Environment,Project", "-o", "csv"},
+			expectedExitCode: 1,
+			expectedError:    true,
+			expectedOutput:   []string{"file,line,resource_type,resource_name,rule,tag,status", ",missing-tag,Project,violation"},
+		},
+		{
+			name:             "csv output include compliant",
+			filePathOrDir:    "testdata/terraform/functions.tf",
+			cliArgs:          []string{"--tags", "Owner,Environment", "-o", "csv", "--include-compliant"},
+			expectedExitCode: 0,
+			expectedError:    false,
+			expectedOutput:   []string{"tag:Environment,tag:Owner", ",aws_s3_bucket,this,,,compliant,dev,jakebark"},
+		},
+		{
+			name:             "tsv output",
+			filePathOrDir:    "testdata/terraform/tags.tf",
+			cliArgs:          []string{"--tags", "Owner,Environment,Project", "-o", "tsv"},
+			expectedExitCode: 1,
+			expectedError:    true,
+			expectedOutput:   []string{"file\tline\tresource_type\tresource_name\trule\ttag\tstatus", "\tmissing-tag\tProject\tviolation"},
+		},
+		{
+			name:             "html output",
+			filePathOrDir:    "testdata/terraform/tags.tf",
+			cliArgs:          []string{"--tags", "Owner,Environment,Project", "-o", "html"},
+			expectedExitCode: 1,
+			expectedError:    true,
+			expectedOutput:   []string{"<!DOCTYPE html>", `<div class="bar-label">Project</div>`, "<code>aws_s3_bucket.this</code>"},
+		},
+		{
+			name:             "sarif output",
+			filePathOrDir:    "testdata/terraform/tags.tf",
+			cliArgs:          []string{"--tags", "Owner,Environment,Project", "-o", "sarif"},
+			expectedExitCode: 1,
+			expectedError:    true,
+			expectedOutput:   []string{`"id": "missing-tag/Owner"`, `"ruleId": "missing-tag/Project"`, `"endLine":`, `"partialFingerprints"`, `"insertedContent"`},
+		},
+		{
+			name:             "junit output per file",
+			filePathOrDir:    "testdata/terraform/ignore_all.tf",
+			cliArgs:          []string{"--tags", "Owner,Environment,Project", "-o", "junit-xml", "--junit-per-file"},
+			expectedExitCode: 0,
+			expectedError:    false,
+			expectedOutput:   []string{"<testsuites name=\"tag-nag\"", "<testsuite name=\"testdata/terraform/ignore_all.tf\"", "<skipped"},
+		},
+		{
+			name:             "sort by tag",
+			filePathOrDir:    "testdata/terraform/tags.tf",
+			cliArgs:          []string{"--tags", "Owner,Environment,Project", "--sort-by", "tag"},
+			expectedExitCode: 1,
+			expectedError:    true,
+			expectedOutput:   []string{"Violation(s) of Project", "testdata/terraform/tags.tf:1: aws_s3_bucket \"this\""},
+		},
+		{
+			name:             "invalid sort order",
+			filePathOrDir:    "testdata/terraform/tags.tf",
+			cliArgs:          []string{"--tags", "Owner", "--sort-by", "severity"},
+			expectedExitCode: 2,
+			expectedError:    true,
+			expectedOutput:   []string{"invalid sort order 'severity'"},
+		},
+		{
+			name:             "template output",
+			filePathOrDir:    "testdata/terraform/tags.tf",
+			cliArgs:          []string{"--tags", "Owner,Environment,Project", "-o", "template", "--template", "examples/slack.tmpl"},
+			expectedExitCode: 1,
+			expectedError:    true,
+			expectedOutput:   []string{"*tag-nag*: 1 violation(s) in 1 file(s)", "1: `aws_s3_bucket.this` Missing tags: Project"},
+		},
+		{
+			name:             "two stdout outputs",
+			filePathOrDir:    "testdata/terraform/tags.tf",
+			cliArgs:          []string{"--tags", "Owner", "-o", "text", "-o", "json"},
+			expectedExitCode: 2,
+			expectedError:    true,
+			expectedOutput:   []string{"only one output can write to stdout"},
+		},
+		{
+			name:             "coverage footer",
+			filePathOrDir:    "testdata/terraform/tags.tf",
+			cliArgs:          []string{"--tags", "Owner,Environment,Project"},
+			expectedExitCode: 1,
+			expectedError:    true,
+			expectedOutput:   []string{"Coverage: 0/1 resource(s) compliant (0.0%)", "  Project: 0 present, 1 absent, 0 invalid", "  aws_s3_bucket: 0/1 compliant (0.0%)"},
+		},
+		{
+			name:             "json coverage",
+			filePathOrDir:    "testdata/terraform/tags.tf",
+			cliArgs:          []string{"--tags", "Owner,Environment,Project", "-o", "json"},
+			expectedExitCode: 1,
+			expectedError:    true,
+			expectedOutput:   []string{`"coverage": {`, `"resources": 1,`, `"by_type": {`, `"by_directory": {`},
+		},
+		{
+			name:             "max violations",
+			filePathOrDir:    "testdata/terraform/tags.tf",
+			cliArgs:          []string{"--tags", "Owner,Environment,Project", "--max-violations", "1"},
+			expectedExitCode: 0,
+			expectedError:    false,
+			expectedOutput:   []string{"Found 1 tag violation(s)"},
+		},
+		{
+			name:             "min compliance",
+			filePathOrDir:    "testdata/terraform/tags.tf",
+			cliArgs:          []string{"--tags", "Owner,Environment,Project", "--min-compliance", "50"},
+			expectedExitCode: 1,
+			expectedError:    true,
+			expectedOutput:   []string{"0.0% of resources are compliant, below the --min-compliance 50%"},
+		},
+		{
+			name:             "min compliance met",
+			filePathOrDir:    "testdata/terraform",
+			cliArgs:          []string{"--tags", "Owner,Environment,Project", "--min-compliance", "50"},
+			expectedExitCode: 0,
+			expectedError:    false,
+			expectedOutput:   []string{"Coverage: 9/15 resource(s) compliant (60.0%)"},
+		},
+		{
+			name:             "invalid min compliance",
+			filePathOrDir:    "testdata/terraform/tags.tf",
+			cliArgs:          []string{"--tags", "Owner", "--min-compliance", "120"},
+			expectedExitCode: 2,
+			expectedError:    true,
+			expectedOutput:   []string{"invalid min compliance 120"},
+		},
+		{
+			name:             "parse error",
+			filePathOrDir:    "testdata/parse_error/invalid.tf",
+			cliArgs:          []string{"--tags", "Owner"},
+			expectedExitCode: 0,
+			expectedError:    false,
+			expectedOutput:   []string{"1: file 🏷️  Parse error: Unclosed configuration block", "[warning]", "Could not parse 1 file(s)"},
+		},
+		{
+			name:             "parse error strict",
+			filePathOrDir:    "testdata/parse_error/invalid.tf",
+			cliArgs:          []string{"--tags", "Owner", "--strict"},
+			expectedExitCode: 3,
+			expectedError:    true,
+			expectedOutput:   []string{"Parse error: Unclosed configuration block", "Could not parse 1 file(s)"},
+		},
+		{
+			name:             "parse error fail on warning",
+			filePathOrDir:    "testdata/parse_error/invalid.tf",
+			cliArgs:          []string{"--tags", "Owner", "--fail-on", "warning"},
+			expectedExitCode: 1,
+			expectedError:    true,
+			expectedOutput:   []string{"Could not parse 1 file(s)"},
+		},
+		{
+			name:             "parse error json",
+			filePathOrDir:    "testdata/parse_error/invalid.tf",
+			cliArgs:          []string{"--tags", "Owner", "-o", "json"},
+			expectedExitCode: 0,
+			expectedError:    false,
+			expectedOutput:   []string{`"rule_id": "parse-error"`, `"message": "Unclosed configuration block`, `"line": 1`, `"severity": "warning"`},
+		},
+		{
+			name:             "parse error strict json",
+			filePathOrDir:    "testdata/parse_error/invalid.tf",
+			cliArgs:          []string{"--tags", "Owner", "--strict", "-o", "json"},
+			expectedExitCode: 3,
+			expectedError:    true,
+			expectedOutput:   []string{`"rule_id": "parse-error"`, `"severity": "error"`},
+		},
+		{
+			name:             "parse error dry run",
+			filePathOrDir:    "testdata/parse_error/invalid.tf",
+			cliArgs:          []string{"--tags", "Owner", "--dry-run"},
+			expectedExitCode: 0,
+			expectedError:    false,
+			expectedOutput:   []string{"Could not parse 1 file(s)"},
+		},
+		{
+			name:             "github output",
+			filePathOrDir:    "testdata/terraform/ignore.tf",
+			cliArgs:          []string{"--tags", "Owner,Environment,Project", "-o", "github"},
+			expectedExitCode: 0,
+			expectedError:    false,
+			expectedOutput:   []string{`::notice file=testdata/terraform/ignore.tf,line=1,title=tag-nag::aws_s3_bucket "this": skipped`},
+		},
 		{
 			name:             "lower function",
 			filePathOrDir:    "testdata/terraform/function.tf",
@@ -304,6 +561,22 @@ func TestTerraform(t *testing.T) {
 			expectedError:    false,
 			expectedOutput:   []string{"No tag violations found"},
 		},
+		{
+			name:             "case duplicate",
+			filePathOrDir:    "testdata/terraform/key_style.tf",
+			cliArgs:          []string{"--tags", "Owner"},
+			expectedExitCode: 1,
+			expectedError:    true,
+			expectedOutput:   []string{`aws_s3_bucket "this"`, "Key issues: Owner/owner (case duplicate)"},
+		},
+		{
+			name:             "key style",
+			filePathOrDir:    "testdata/terraform/key_style.tf",
+			cliArgs:          []string{"--tags", "Owner", "--key-style", "PascalCase"},
+			expectedExitCode: 1,
+			expectedError:    true,
+			expectedOutput:   []string{"cost-center (not PascalCase)", "owner (not PascalCase)"},
+		},
 		{
 			name:             "skip file",
 			filePathOrDir:    "testdata/terraform",
@@ -315,7 +588,7 @@ func TestTerraform(t *testing.T) {
 		{
 			name:             "skip directory",
 			filePathOrDir:    "testdata",
-			cliArgs:          []string{"--tags", "Owner,Environment,Project", "-s", "testdata/terraform"},
+			cliArgs:          []string{"--tags", "Owner,Environment,Project", "-s", "testdata/terraform,testdata/config,testdata/parse_error"},
 			expectedExitCode: 1,
 			expectedError:    true,
 			expectedOutput:   []string{`AWS::S3::Bucket "this"`, "Missing tags: Project"},
@@ -405,6 +678,118 @@ func TestCloudFormation(t *testing.T) {
 			expectedError:    true,
 			expectedOutput:   []string{`AWS::S3::Bucket "this"`, "Missing tags: owner"},
 		},
+		{
+			name:             "yaml parse error",
+			filePathOrDir:    "testdata/config/invalid_syntax.yml",
+			cliArgs:          []string{"--tags", "Owner", "--strict"},
+			expectedExitCode: 3,
+			expectedError:    true,
+			expectedOutput:   []string{"2: file 🏷️  Parse error: did not find expected ',' or ']'"},
+		},
+		{
+			name:             "skips files that are not templates",
+			filePathOrDir:    "testdata",
+			cliArgs:          []string{"--tags", "Owner,Environment,Project", "-s", "testdata/terraform,testdata/parse_error"},
+			expectedExitCode: 1,
+			expectedError:    true,
+			expectedOutput:   []string{`AWS::S3::Bucket "this"`},
+		},
+		{
+			name:             "cfn include",
+			filePathOrDir:    "testdata",
+			cliArgs:          []string{"--tags", "Owner,Environment,Project", "--cfn-include", "cloudformation/*.yml", "-s", "testdata/terraform,testdata/parse_error"},
+			expectedExitCode: 1,
+			expectedError:    true,
+			expectedOutput:   []string{"Violation(s) in testdata/cloudformation/tags.yml"},
+		},
+		{
+			name:             "invalid cfn include",
+			filePathOrDir:    "testdata/cloudformation",
+			cliArgs:          []string{"--tags", "Owner", "--cfn-include", "templates/[.yaml"},
+			expectedExitCode: 2,
+			expectedError:    true,
+			expectedOutput:   []string{"Error parsing cfn include: invalid include glob 'templates/[.yaml'"},
+		},
+		{
+			name:             "cdk assembly",
+			filePathOrDir:    "testdata/cdk",
+			cliArgs:          []string{"--tags", "Owner,Environment"},
+			expectedExitCode: 1,
+			expectedError:    true,
+			expectedOutput:   []string{`AWS::DynamoDB::Table "TableCD117FA1" (AppStack/Database/Table/Resource)`, "Coverage: 1/3 resource(s) compliant"},
+		},
+		{
+			name:             "cdk nested stack inherits parent tags",
+			filePathOrDir:    "testdata/cdk_nested",
+			cliArgs:          []string{"--tags", "Owner,Environment"},
+			expectedExitCode: 0,
+			expectedError:    false,
+			expectedOutput:   []string{"Coverage: 2/2 resource(s) compliant", "No tag violations found"},
+		},
+		{
+			name:             "cdk nested stack inherited value",
+			filePathOrDir:    "testdata/cdk_nested",
+			cliArgs:          []string{"--tags", "Owner,Environment[prod]"},
+			expectedExitCode: 1,
+			expectedError:    true,
+			expectedOutput:   []string{`AWS::SQS::Queue "Queue4A7E3555" (ApiStack/Queues/Queue/Resource) 🏷️  Invalid values: Environment[prod] (found "dev" in stack tags)`},
+		},
+		{
+			name:             "cdk nested stack inherited value json",
+			filePathOrDir:    "testdata/cdk_nested",
+			cliArgs:          []string{"--tags", "Owner,Environment[prod]", "-o", "json"},
+			expectedExitCode: 1,
+			expectedError:    true,
+			expectedOutput:   []string{`"actual": "dev"`, `"from_default_tags": false`, `"inherited_from": "stack tags"`},
+		},
+		{
+			name:             "nested stack inherits parent tags",
+			filePathOrDir:    "testdata/nested_stacks",
+			cliArgs:          []string{"--tags", "Owner"},
+			expectedExitCode: 0,
+			expectedError:    false,
+			expectedOutput:   []string{"Coverage: 3/3 resource(s) compliant", "No tag violations found"},
+		},
+		{
+			name:             "nested stack cycle",
+			filePathOrDir:    "testdata/nested_cycle",
+			cliArgs:          []string{"--tags", "Owner"},
+			expectedExitCode: 1,
+			expectedError:    true,
+			expectedOutput:   []string{`AWS::S3::Bucket "BucketA" 🏷️  Missing tags: Owner`, "Coverage: 3/4 resource(s) compliant"},
+		},
+		{
+			name:             "nested stack shared by two parents",
+			filePathOrDir:    "testdata/nested_shared",
+			cliArgs:          []string{"--tags", "Owner"},
+			expectedExitCode: 1,
+			expectedError:    true,
+			expectedOutput:   []string{"Found 3 tag violation(s)", "Coverage: 0/3 resource(s) compliant"},
+		},
+		{
+			name:             "template config tags",
+			filePathOrDir:    "testdata/nested_stacks",
+			cliArgs:          []string{"--tags", "Owner,Environment[prod]", "--template-config", "testdata/nested_stacks/template-config.json"},
+			expectedExitCode: 0,
+			expectedError:    false,
+			expectedOutput:   []string{"No tag violations found"},
+		},
+		{
+			name:             "stack tags",
+			filePathOrDir:    "testdata/nested_stacks",
+			cliArgs:          []string{"--tags", "Owner,Environment[dev]", "--stack-tags", "Environment=prod"},
+			expectedExitCode: 1,
+			expectedError:    true,
+			expectedOutput:   []string{`AWS::EC2::VPC "Vpc" 🏷️  Invalid values: Environment[dev] (found "prod" in stack tags)`},
+		},
+		{
+			name:             "invalid stack tags",
+			filePathOrDir:    "testdata/nested_stacks",
+			cliArgs:          []string{"--tags", "Owner", "--stack-tags", "Environment"},
+			expectedExitCode: 2,
+			expectedError:    true,
+			expectedOutput:   []string{"Error parsing stack tags: invalid stack tag 'Environment'. Expected Key=Value"},
+		},
 	}
 
 	for _, tc := range testCases {
@@ -431,3 +816,35 @@ func TestCloudFormation(t *testing.T) {
 		})
 	}
 }
+
+func TestMultipleOutputs(t *testing.T) {
+	dir := t.TempDir()
+	sarifFile := filepath.Join(dir, "tag-nag.sarif")
+	junitFile := filepath.Join(dir, "tag-nag.xml")
+
+	output, _, exitCode := runTagNag(t, "testdata/terraform/tags.tf", "--tags", "Owner,Environment,Project",
+		"-o", "text", "-o", "sarif="+sarifFile, "--output", "junit-xml="+junitFile)
+	if exitCode != 1 {
+		t.Errorf("Expected exit code 1, got %d. Output:\n%s", exitCode, output)
+	}
+	if !strings.Contains(output, "Missing tags: Project") {
+		t.Errorf("Output missing text report. Output:\n%s", output)
+	}
+
+	expectedFiles := map[string]string{
+		sarifFile: `"ruleId": "missing-tag/Project"`,
+		junitFile: `<failure message="Missing tags: Project">`,
+	}
+	for file, expectedStr := range expectedFiles {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			t.Fatalf("Expected output file %s: %v", file, err)
+		}
+		if !strings.Contains(string(data), expectedStr) {
+			t.Errorf("%s missing expected string '%s':\n%s", file, expectedStr, data)
+		}
+		if !strings.Contains(output, "Output written to "+file) {
+			t.Errorf("Output missing write message for %s. Output:\n%s", file, output)
+		}
+	}
+}
@@ -0,0 +1,10 @@
+#tag-nag ignore
+resource "random_id" "suffix" {
+  byte_length = 4
+}
+
+resource "aws_s3_bucket" "this" {
+  tags = {
+    Owner = "platform"
+  }
+}
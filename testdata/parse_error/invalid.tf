@@ -0,0 +1,6 @@
+resource "aws_s3_bucket" "this" {
+  bucket = "my-bucket"
+  tags = {
+    Owner = "jake"
+  
+}
@@ -0,0 +1,16 @@
+resource "aws_s3_bucket" "partial" {
+  #tag-nag ignore Project reason="legacy bucket"
+  bucket = "partial-bucket"
+  tags = {
+    Owner = "jakebark"
+  }
+}
+
+resource "aws_s3_bucket" "expired" {
+  #tag-nag ignore reason="migration" until=2020-01-01
+  bucket = "expired-bucket"
+  tags = {
+    Owner       = "jakebark"
+    Environment = "dev"
+  }
+}
@@ -0,0 +1,9 @@
+resource "aws_s3_bucket" "this" {
+  bucket = "test-bucket"
+  tags = {
+    Owner       = "jakebark"
+    owner       = "jakebark"
+    Environment = "dev"
+    cost-center = "112233"
+  }
+}
@@ -0,0 +1,14 @@
+#tag-nag ignore
+locals {
+  name = "unused"
+}
+
+resource "aws_s3_bucket" "this" {
+  #tag-nag ignore reason="tags added"
+  bucket = "test-bucket"
+  tags = {
+    Owner       = "jakebark"
+    Environment = "dev"
+    Project     = "tag-nag"
+  }
+}